@@ -0,0 +1,278 @@
+package onylogger
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	pagerDutyEventsURL   = "https://events.pagerduty.com/v2/enqueue"
+	opsgenieAlertsURL    = "https://api.opsgenie.com/v2/alerts"
+	defaultAlertCooldown = 5 * time.Minute
+)
+
+// AlertConfig tunes the incident hooks added with WithPagerDuty and
+// WithOpsgenie. Fatal entries always open an incident.
+type AlertConfig struct {
+	// ErrorBurst, when non-zero, opens an incident once more than this many
+	// Error entries are logged within ErrorWindow.
+	ErrorBurst  int
+	ErrorWindow time.Duration
+
+	// Cooldown suppresses repeated incidents with the same dedup key.
+	// Defaults to five minutes.
+	Cooldown time.Duration
+
+	// Source identifies this process in the incident; defaults to the
+	// hostname.
+	Source string
+
+	// Client sends the requests; defaults to one with a 10 second timeout
+	// that honors the proxy environment variables (see ProxyClient).
+	Client *http.Client
+	// Retry decides whether failed deliveries are tried again; defaults to
+	// ExponentialBackoff.
+	Retry RetryPolicy
+}
+
+// alert is a provider-neutral incident.
+type alert struct {
+	summary  string
+	dedupKey string
+	severity logrus.Level
+	source   string
+	time     time.Time
+	details  map[string]string
+}
+
+// alertSender delivers one alert to a specific service.
+type alertSender func(client *http.Client, a alert) error
+
+// WithPagerDuty opens PagerDuty incidents through the Events API v2.
+func WithPagerDuty(routingKey string, cfg AlertConfig) Option {
+	return func(o *options) {
+		o.alerts = append(o.alerts, alertHookConfig{cfg: cfg, send: pagerDutySender(routingKey)})
+	}
+}
+
+// WithOpsgenie opens Opsgenie alerts through the Alert API.
+func WithOpsgenie(apiKey string, cfg AlertConfig) Option {
+	return func(o *options) {
+		o.alerts = append(o.alerts, alertHookConfig{cfg: cfg, send: opsgenieSender(apiKey)})
+	}
+}
+
+type alertHookConfig struct {
+	cfg  AlertConfig
+	send alertSender
+}
+
+func pagerDutySender(routingKey string) alertSender {
+	severities := map[logrus.Level]string{
+		logrus.PanicLevel: "critical",
+		logrus.FatalLevel: "critical",
+		logrus.ErrorLevel: "error",
+	}
+	return func(client *http.Client, a alert) error {
+		return postJSON(client, pagerDutyEventsURL, nil, map[string]interface{}{
+			"routing_key":  routingKey,
+			"event_action": "trigger",
+			"dedup_key":    a.dedupKey,
+			"payload": map[string]interface{}{
+				"summary":        truncate(a.summary, 1024),
+				"source":         a.source,
+				"severity":       severities[a.severity],
+				"timestamp":      a.time.Format(time.RFC3339),
+				"custom_details": a.details,
+			},
+		})
+	}
+}
+
+func opsgenieSender(apiKey string) alertSender {
+	priorities := map[logrus.Level]string{
+		logrus.PanicLevel: "P1",
+		logrus.FatalLevel: "P1",
+		logrus.ErrorLevel: "P2",
+	}
+	header := http.Header{"Authorization": {"GenieKey " + apiKey}}
+	return func(client *http.Client, a alert) error {
+		return postJSON(client, opsgenieAlertsURL, header, map[string]interface{}{
+			"message":     truncate(a.summary, 130),
+			"alias":       a.dedupKey,
+			"description": a.summary,
+			"priority":    priorities[a.severity],
+			"source":      a.source,
+			"details":     a.details,
+		})
+	}
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// Back up to a rune boundary to keep the result valid UTF-8.
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// dedupKey derives a stable key from a message. Digits are dropped so that
+// "retry 3 of 5 failed" and "retry 4 of 5 failed" land on one incident.
+func dedupKey(message string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if unicode.IsDigit(r) {
+			return -1
+		}
+		return r
+	}, message)
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:16])
+}
+
+type alertHook struct {
+	cfg    AlertConfig
+	send   alertSender
+	report func(error)
+
+	queue chan alert
+	done  chan struct{}
+
+	mu     sync.Mutex
+	sent   map[string]time.Time // dedup key -> last incident
+	errors []time.Time          // Error entries inside the burst window
+
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+func (l *OnyLogger) addAlert(hc alertHookConfig) {
+	cfg := hc.cfg
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = defaultAlertCooldown
+	}
+	if cfg.Source == "" {
+		cfg.Source, _ = os.Hostname()
+	}
+	if cfg.ErrorBurst > 0 && cfg.ErrorWindow == 0 {
+		cfg.ErrorWindow = time.Minute
+	}
+
+	h := &alertHook{
+		cfg:    cfg,
+		send:   hc.send,
+		report: l.reportError,
+		queue:  make(chan alert, 16),
+		done:   make(chan struct{}),
+		sent:   make(map[string]time.Time),
+	}
+	go h.run()
+	l.closers = append(l.closers, h)
+	l.AddHook(h)
+}
+
+func (h *alertHook) Levels() []logrus.Level {
+	if h.cfg.ErrorBurst > 0 {
+		return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+	}
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel}
+}
+
+func (h *alertHook) Fire(entry *logrus.Entry) error {
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if h.closed || demoted(entry, h.Levels()) {
+		return nil
+	}
+
+	message := plainLinks(entry.Message)
+	a := alert{
+		summary:  message,
+		dedupKey: dedupKey(message),
+		severity: entry.Level,
+		source:   h.cfg.Source,
+		time:     entry.Time,
+		details:  make(map[string]string),
+	}
+	for k, v := range newEntry(entry).Fields {
+		a.details[k] = fmt.Sprint(v)
+	}
+
+	if entry.Level == logrus.ErrorLevel {
+		n, burst := h.recordError(entry.Time)
+		if !burst {
+			return nil
+		}
+		a.summary = fmt.Sprintf("%d errors within %s, latest: %s", n, h.cfg.ErrorWindow, message)
+		a.dedupKey = dedupKey("error-burst " + h.cfg.Source)
+	}
+
+	if h.suppressed(a.dedupKey, entry.Time) {
+		return nil
+	}
+	h.queue <- a
+	return nil
+}
+
+// recordError tracks an Error entry and reports whether the burst
+// threshold is now exceeded.
+func (h *alertHook) recordError(t time.Time) (int, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	cutoff := t.Add(-h.cfg.ErrorWindow)
+	kept := h.errors[:0]
+	for _, at := range h.errors {
+		if at.After(cutoff) {
+			kept = append(kept, at)
+		}
+	}
+	h.errors = append(kept, t)
+	return len(h.errors), len(h.errors) > h.cfg.ErrorBurst
+}
+
+func (h *alertHook) suppressed(key string, t time.Time) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if last, ok := h.sent[key]; ok && t.Sub(last) < h.cfg.Cooldown {
+		return true
+	}
+	h.sent[key] = t
+	return false
+}
+
+func (h *alertHook) run() {
+	defer close(h.done)
+	for a := range h.queue {
+		if err := deliver(h.cfg.Retry, func() error { return h.send(h.cfg.Client, a) }); err != nil {
+			h.report(fmt.Errorf("failed to open incident: %w", err))
+		}
+	}
+}
+
+// Close waits for pending incidents to be sent.
+func (h *alertHook) Close() error {
+	h.closeMu.Lock()
+	defer h.closeMu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+
+	close(h.queue)
+	<-h.done
+	return nil
+}
@@ -0,0 +1,263 @@
+package onylogger
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const defaultArchiveKey = `{{.Hostname}}/{{.Date}}/{{.Filename}}`
+
+// ArchiveConfig describes the S3 archiver added with WithArchive.
+type ArchiveConfig struct {
+	// Pattern is a filepath.Match glob selecting rotated files to upload,
+	// e.g. "/var/log/app/*.log.*". Files the logger writes to are skipped.
+	Pattern string
+	// Interval between scans; defaults to five minutes.
+	Interval time.Duration
+
+	// Endpoint of the S3-compatible service, e.g. "https://minio:9000".
+	// Defaults to AWS for Region.
+	Endpoint  string
+	Region    string
+	Bucket    string
+	AccessKey string
+	SecretKey string
+	// PathStyle addresses the bucket in the path (endpoint/bucket/key)
+	// instead of the host name, as most self-hosted services need.
+	PathStyle bool
+
+	// Key is a text/template for the object key, executed with an
+	// ArchiveObject. Defaults to "{{.Hostname}}/{{.Date}}/{{.Filename}}".
+	Key string
+
+	// Client sends the requests; defaults to one with a 10 second timeout
+	// that honors the proxy environment variables (see ProxyClient).
+	Client *http.Client
+}
+
+// ArchiveObject is the data the object key template is executed with.
+type ArchiveObject struct {
+	Hostname string
+	Filename string    // base name of the uploaded object, ending in ".gz"
+	Date     string    // modification date of the file, "2006/01/02"
+	Time     time.Time // modification time of the file
+}
+
+// WithArchive periodically gzips files matching cfg.Pattern, uploads them
+// to S3-compatible storage, and deletes the local copy once the upload
+// succeeded.
+func WithArchive(cfg ArchiveConfig) Option {
+	return func(o *options) {
+		o.archives = append(o.archives, cfg)
+	}
+}
+
+type archiver struct {
+	cfg      ArchiveConfig
+	key      *template.Template
+	s3       *s3Client
+	hostname string
+	skip     map[string]bool
+	files    []*logFile
+	report   func(error)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (l *OnyLogger) addArchive(cfg ArchiveConfig) {
+	if cfg.Interval == 0 {
+		cfg.Interval = 5 * time.Minute
+	}
+	if cfg.Key == "" {
+		cfg.Key = defaultArchiveKey
+	}
+	if cfg.Endpoint == "" {
+		cfg.Endpoint = "https://s3." + cfg.Region + ".amazonaws.com"
+	}
+
+	key, err := template.New("key").Parse(cfg.Key)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Invalid archive key template: %v", err)
+		return
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Invalid archive endpoint %s: %v", cfg.Endpoint, err)
+		return
+	}
+
+	a := &archiver{
+		cfg:    cfg,
+		key:    key,
+		report: l.reportError,
+		s3: &s3Client{
+			endpoint:  endpoint,
+			region:    cfg.Region,
+			bucket:    cfg.Bucket,
+			accessKey: cfg.AccessKey,
+			secretKey: cfg.SecretKey,
+			pathStyle: cfg.PathStyle,
+			http:      cfg.Client,
+		},
+		skip:  make(map[string]bool),
+		files: l.files,
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+	a.hostname, _ = os.Hostname()
+	for _, fc := range l.opts.files {
+		if abs, err := filepath.Abs(fc.path); err == nil {
+			a.skip[abs] = true
+		}
+	}
+
+	go a.run()
+	l.closers = append(l.closers, a)
+}
+
+func (a *archiver) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		a.archiveAll()
+		select {
+		case <-a.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (a *archiver) archiveAll() {
+	matches, err := filepath.Glob(a.cfg.Pattern)
+	if err != nil {
+		a.report(fmt.Errorf("invalid archive pattern %s: %w", a.cfg.Pattern, err))
+		return
+	}
+	for _, path := range matches {
+		if abs, err := filepath.Abs(path); err == nil && a.skip[abs] {
+			continue
+		}
+		if a.compressing(path) {
+			continue // next pass
+		}
+		if stem, ok := strings.CutSuffix(path, ".gz"); ok && fileExists(stem) {
+			continue // left behind with its file, archived below
+		}
+		if err := a.archive(path); err != nil {
+			a.report(fmt.Errorf("failed to archive %s: %w", path, err))
+			continue
+		}
+		// A compressed copy left behind, by a crash for one, is superseded
+		// by the upload.
+		if gz := path + ".gz"; fileExists(gz) {
+			if err := os.Remove(gz); err != nil {
+				a.report(fmt.Errorf("failed to remove %s: %w", gz, err))
+			}
+		}
+	}
+}
+
+// compressing reports whether path is a rotated file being compressed, or
+// the partial compressed copy of one: by one of the logger's files, or,
+// while the file and its .gz both exist and the .gz is still being
+// written to, by another process.
+func (a *archiver) compressing(path string) bool {
+	for _, lf := range a.files {
+		if lf.owns(path) {
+			return true
+		}
+	}
+	stem, ok := strings.CutSuffix(path, ".gz")
+	if !ok {
+		stem = path
+	}
+	return fileExists(stem) && fileExists(stem+".gz") && !a.stale(stem+".gz")
+}
+
+// stale reports whether path was last written more than two intervals
+// ago, long enough for any compression to have ended.
+func (a *archiver) stale(path string) bool {
+	fi, err := os.Stat(path)
+	return err == nil && time.Since(fi.ModTime()) > 2*a.cfg.Interval
+}
+
+func (a *archiver) archive(path string) error {
+	fi, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil // removed since the scan
+	}
+	if err != nil || !fi.Mode().IsRegular() {
+		return err
+	}
+
+	body, err := gzipFile(path)
+	if err != nil {
+		return err
+	}
+
+	name := filepath.Base(path)
+	if !strings.HasSuffix(name, ".gz") {
+		name += ".gz"
+	}
+	var key strings.Builder
+	err = a.key.Execute(&key, ArchiveObject{
+		Hostname: a.hostname,
+		Filename: name,
+		Date:     fi.ModTime().Format("2006/01/02"),
+		Time:     fi.ModTime(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := a.s3.put(strings.TrimPrefix(key.String(), "/"), body, "application/gzip"); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// gzipFile returns the compressed contents of path, or the contents as-is
+// if the file is already gzipped.
+func gzipFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if strings.HasSuffix(path, ".gz") {
+		return io.ReadAll(f)
+	}
+
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	if _, err := io.Copy(zw, f); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (a *archiver) Close() error {
+	close(a.stop)
+	<-a.done
+	return nil
+}
@@ -0,0 +1,254 @@
+package onylogger
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BackpressurePolicy decides what an async queue does when it is full.
+type BackpressurePolicy int
+
+const (
+	// Block makes the logging call wait until the queue has room.
+	Block BackpressurePolicy = iota
+	// DropOldest discards the oldest queued entry to make room for the new one.
+	DropOldest
+	// DropNewest discards the entry being logged.
+	DropNewest
+)
+
+// QueueConfig sets how entries are queued for one output, overriding
+// WithAsync for it; see WithConsoleQueue, FileQueue and SocketQueue, and
+// the Queue field of the network sink configs.
+//
+//	onylogger.WithConsoleQueue(onylogger.QueueConfig{}) // synchronous
+//	onylogger.SocketQueue(onylogger.QueueConfig{Size: 10000, FlushInterval: time.Second})
+type QueueConfig struct {
+	// Size is how many entries are queued; 0 writes to the output on the
+	// logging goroutine.
+	Size int
+	// Workers is the number of goroutines writing queued entries; defaults
+	// to 1. With more, entries may reach the output out of order.
+	Workers int
+	// FlushInterval, when set, has the workers write what was queued once
+	// per interval rather than as entries come in.
+	FlushInterval time.Duration
+	// Policy decides what happens to new entries once the queue is full.
+	Policy BackpressurePolicy
+}
+
+type queued struct {
+	p       []byte
+	flushed *sync.WaitGroup // set for flush markers instead of p
+}
+
+// asyncWriter queues writes for background workers to hand to out.
+type asyncWriter struct {
+	out      io.Writer
+	policy   BackpressurePolicy
+	queue    chan queued
+	workers  int
+	interval time.Duration
+	wake     chan struct{} // has interval workers write before the tick
+
+	// onResume is called (on its own goroutine) with the number of entries
+	// dropped once the queue accepts entries again.
+	onResume func(dropped uint64)
+
+	mu      sync.RWMutex
+	closed  bool
+	flushMu sync.Mutex // one flush at a time, so markers do not interleave
+	running sync.WaitGroup
+	dropped atomic.Uint64 // total over the writer's lifetime
+	pending atomic.Uint64 // dropped since the last onResume report
+}
+
+func newAsyncWriter(out io.Writer, q QueueConfig) *asyncWriter {
+	w := &asyncWriter{
+		out:      out,
+		policy:   q.Policy,
+		queue:    make(chan queued, q.Size),
+		workers:  max(q.Workers, 1),
+		interval: q.FlushInterval,
+	}
+	w.wake = make(chan struct{}, w.workers)
+	w.running.Add(w.workers)
+	for i := 0; i < w.workers; i++ {
+		go w.run()
+	}
+	return w
+}
+
+func (w *asyncWriter) run() {
+	defer w.running.Done()
+	if w.interval <= 0 {
+		for item := range w.queue {
+			w.write(item)
+		}
+		return
+	}
+
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-w.wake:
+		}
+		// Write what is queued now; entries coming in meanwhile wait for
+		// the next tick.
+		for n := len(w.queue); n > 0; n-- {
+			item, ok := <-w.queue
+			if !ok {
+				return
+			}
+			w.write(item)
+		}
+		if w.drained() {
+			return
+		}
+	}
+}
+
+// drained reports whether the queue is closed and empty.
+func (w *asyncWriter) drained() bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.closed && len(w.queue) == 0
+}
+
+func (w *asyncWriter) write(item queued) {
+	if item.flushed == nil {
+		w.out.Write(item.p)
+		return
+	}
+	// Each worker takes one marker and waits for the others, so that none
+	// is still writing an entry queued before the flush.
+	item.flushed.Done()
+	item.flushed.Wait()
+}
+
+func (w *asyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return w.out.Write(p)
+	}
+
+	// logrus reuses its buffer once Write returns.
+	item := queued{p: append([]byte(nil), p...)}
+
+	switch w.policy {
+	case DropNewest:
+		select {
+		case w.queue <- item:
+		default:
+			w.drop()
+			return len(p), nil
+		}
+	case DropOldest:
+		evicted := false
+		for !w.tryEnqueue(item) {
+			w.evictOldest()
+			evicted = true
+		}
+		if evicted {
+			return len(p), nil
+		}
+	default:
+		w.queue <- item
+	}
+
+	// The queue took an entry without dropping: report any earlier losses.
+	if w.pending.Load() > 0 {
+		w.resume()
+	}
+	return len(p), nil
+}
+
+func (w *asyncWriter) tryEnqueue(item queued) bool {
+	select {
+	case w.queue <- item:
+		return true
+	default:
+		return false
+	}
+}
+
+func (w *asyncWriter) evictOldest() {
+	select {
+	case old := <-w.queue:
+		if old.flushed != nil {
+			// Everything queued before the marker is already with a worker.
+			old.flushed.Done()
+			return
+		}
+		w.drop()
+	default:
+	}
+}
+
+func (w *asyncWriter) drop() {
+	w.dropped.Add(1)
+	w.pending.Add(1)
+}
+
+func (w *asyncWriter) resume() {
+	n := w.pending.Swap(0)
+	if n > 0 && w.onResume != nil {
+		go w.onResume(n)
+	}
+}
+
+// Flush blocks until everything queued so far has been written. Workers
+// that write once per interval are woken rather than waited for.
+func (w *asyncWriter) Flush() error {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+
+	if w.closed {
+		return nil
+	}
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	flushed := new(sync.WaitGroup)
+	flushed.Add(w.workers)
+	for i := 0; i < w.workers; i++ {
+		w.queue <- queued{flushed: flushed}
+	}
+	for i := 0; i < w.workers; i++ {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	flushed.Wait()
+	return nil
+}
+
+// Close drains the queue and stops the workers. Later writes go straight
+// to out.
+func (w *asyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		return nil
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	// Interval workers would otherwise write the rest on the next tick.
+	for i := 0; i < w.workers; i++ {
+		select {
+		case w.wake <- struct{}{}:
+		default:
+		}
+	}
+	w.running.Wait()
+	return nil
+}
@@ -0,0 +1,62 @@
+package onylogger
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// backend is the engine entries are logged through. The package's own
+// methods, like Logfe, Diff and Summary, only go through it, so that another
+// engine (slog, or a native one) can be put behind them; the methods of the
+// embedded logrus.Logger, like Info and WithField, stay part of the API.
+type backend interface {
+	// enabled reports whether entries at level are logged.
+	enabled(level Level) bool
+	// log logs an entry if its level is enabled. t is the entry's time, or
+	// the zero time for now; fields may hold the control fields (see
+	// isInternalField). At PanicLevel it panics once the entry is written,
+	// but at FatalLevel it returns; exit is a separate step.
+	log(level Level, t time.Time, fields Fields, message string)
+	// exit ends the process the way Fatal does, after Close.
+	exit(code int)
+}
+
+// logrusBackend logs through a logrus.Logger, whose hooks feed the sinks.
+type logrusBackend struct {
+	logger *logrus.Logger
+}
+
+func (b logrusBackend) enabled(level Level) bool {
+	return b.logger.IsLevelEnabled(level)
+}
+
+func (b logrusBackend) log(level Level, t time.Time, fields Fields, message string) {
+	entry := b.logger.WithFields(fields)
+	if !t.IsZero() {
+		entry = entry.WithTime(t)
+	}
+	entry.Log(level, message)
+}
+
+func (b logrusBackend) exit(code int) {
+	b.logger.Exit(code)
+}
+
+func (l *OnyLogger) enabled(level Level) bool {
+	return l.backend.enabled(level)
+}
+
+// emit logs message with fields through the backend.
+func (l *OnyLogger) emit(level Level, fields Fields, message string) {
+	l.backend.log(level, time.Time{}, fields, message)
+}
+
+// emitf is emit with a printf-style message, formatted only if level is
+// enabled.
+func (l *OnyLogger) emitf(level Level, fields Fields, format string, args ...interface{}) {
+	if l.enabled(level) {
+		l.emit(level, fields, fmt.Sprintf(format, args...))
+	}
+}
@@ -0,0 +1,33 @@
+package onylogger
+
+import (
+	"bytes"
+
+	"github.com/sirupsen/logrus"
+)
+
+const bell = '\a'
+
+// WithBellOnError rings the terminal bell when an Error or Fatal entry is
+// printed to the console. The bell travels with the entry through the render
+// coordinator, so entries that are dropped never ring, and it is left out
+// when the console is not a terminal.
+func WithBellOnError() Option {
+	return func(o *options) {
+		o.bell = true
+	}
+}
+
+// ringsBell reports whether the entry should sound the bell.
+func (f *emojiFormatter) ringsBell(e *Entry) bool {
+	return f.bell && e.Level <= logrus.ErrorLevel && e.logType != "input"
+}
+
+// stripBell drops the bell the formatter appended to p, for outputs that are
+// not terminals.
+func stripBell(p []byte) []byte {
+	if i := bytes.LastIndexByte(p, '\n'); i >= 0 && bytes.Equal(p[i+1:], []byte{bell}) {
+		return p[:i+1]
+	}
+	return p
+}
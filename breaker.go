@@ -0,0 +1,126 @@
+package onylogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	defaultBreakerField    = "component"
+	defaultBreakerCooldown = time.Minute
+
+	// maxBreakerKeys bounds the errors tracked at once; past it, those that
+	// are neither counting nor demoted are forgotten.
+	maxBreakerKeys = 1024
+)
+
+// BreakerConfig tunes WithErrorBreaker.
+type BreakerConfig struct {
+	// Field names the component an entry comes from; defaults to
+	// "component". Entries without it are left alone.
+	Field string
+	// Rate is how many identical Error entries a component may log within
+	// a second before the breaker trips.
+	Rate int
+	// Cooldown is how long repeats stay demoted once it tripped; defaults
+	// to a minute.
+	Cooldown time.Duration
+}
+
+// WithErrorBreaker keeps a noisy component from flooding the outputs: once
+// it logs more than cfg.Rate identical Error entries within a second, the
+// further ones are logged at Debug level until the cooldown has passed, and
+// a single Warn says so. Entries count as identical when their messages
+// differ only in digits, as for incident dedup keys.
+//
+//	onylogger.WithErrorBreaker(onylogger.BreakerConfig{Rate: 20})
+func WithErrorBreaker(cfg BreakerConfig) Option {
+	return func(o *options) {
+		if cfg.Field == "" {
+			cfg.Field = defaultBreakerField
+		}
+		if cfg.Cooldown <= 0 {
+			cfg.Cooldown = defaultBreakerCooldown
+		}
+		o.breaker = &cfg
+	}
+}
+
+type breakerKey struct {
+	component string
+	message   string // dedup key of the message
+}
+
+type breakerState struct {
+	window time.Time // start of the second being counted
+	count  int
+	until  time.Time // end of the cooldown
+}
+
+// breaker demotes the repeats of errors that trip it.
+type breaker struct {
+	cfg  BreakerConfig
+	warn func(fields Fields, message string)
+
+	mu   sync.Mutex
+	keys map[breakerKey]*breakerState
+}
+
+func newBreaker(cfg BreakerConfig, warn func(Fields, string)) *breaker {
+	return &breaker{cfg: cfg, warn: warn, keys: make(map[breakerKey]*breakerState)}
+}
+
+func (h *breaker) process(e Entry) (Entry, bool) {
+	component, ok := e.Fields[h.cfg.Field]
+	if e.Level != ErrorLevel || !ok {
+		return e, true
+	}
+	name := fieldString(component)
+	message := plainLinks(e.Message)
+	t := e.Time
+
+	h.mu.Lock()
+	s := h.state(breakerKey{name, dedupKey(message)}, t)
+	var demote, tripped bool
+	switch {
+	case t.Before(s.until):
+		demote = true
+	case t.Sub(s.window) >= time.Second || !s.until.IsZero():
+		// A new second, or the first error after a cooldown.
+		*s = breakerState{window: t, count: 1}
+	default:
+		s.count++
+		if s.count > h.cfg.Rate {
+			s.until = t.Add(h.cfg.Cooldown)
+			demote, tripped = true, true
+		}
+	}
+	h.mu.Unlock()
+
+	if tripped {
+		h.warn(Fields{h.cfg.Field: component}, fmt.Sprintf("%s logged %q more than %d times in a second; repeats are logged at Debug level for %s",
+			name, message, h.cfg.Rate, h.cfg.Cooldown))
+	}
+	if demote {
+		e.Level = DebugLevel
+	}
+	return e, true
+}
+
+// state returns the counts for key. Must be called with mu held.
+func (h *breaker) state(key breakerKey, t time.Time) *breakerState {
+	if s, ok := h.keys[key]; ok {
+		return s
+	}
+	if len(h.keys) >= maxBreakerKeys {
+		for k, s := range h.keys {
+			if t.Sub(s.window) >= time.Second && !t.Before(s.until) {
+				delete(h.keys, k)
+			}
+		}
+	}
+	s := &breakerState{}
+	h.keys[key] = s
+	return s
+}
@@ -0,0 +1,256 @@
+package onylogger
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// maxBufferedEntries bounds the entries a buffered logger holds; past it,
+// the oldest are dropped.
+const maxBufferedEntries = 1000
+
+// BufferedLogger is a logger for one unit of work, typically a request,
+// that holds back its Info, Debug and Trace entries until End: they are
+// dropped if the work succeeded and written in full if it failed, so that
+// the logs of a failure come with everything that led to it, without
+// paying for the detail of every success. Warn entries are written right
+// away, and an Error, Fatal or Panic entry writes the held entries, itself,
+// and every later entry straight away.
+type BufferedLogger struct {
+	*OnyLogger
+	buf *entryBuffer
+}
+
+// Buffered returns a logger holding back entries until End (see
+// BufferedLogger). Like the one Err returns, it writes through l and
+// shares its outputs.
+//
+//	b := log.Buffered()
+//	defer func() { b.End(err) }()
+func (l *OnyLogger) Buffered() *BufferedLogger {
+	root := l
+	if l.root != nil {
+		root = l.root
+	}
+	buf := &entryBuffer{to: root.Logger, holds: InfoLevel, flushes: ErrorLevel}
+	return &BufferedLogger{OnyLogger: l.derive(nil, buf), buf: buf}
+}
+
+// End ends the unit of work: the held entries are written if err is not
+// nil or an error was logged, and dropped otherwise. Entries logged after
+// End are written right away.
+func (b *BufferedLogger) End(err error) {
+	b.buf.end(err != nil)
+}
+
+// entryBuffer holds the entries of a buffered logger or a transaction.
+// Entries at holds or less severe are held until end; one at flushes or
+// more severe writes those held and switches the buffer to writing entries
+// right away.
+type entryBuffer struct {
+	to      *logrus.Logger
+	holds   Level
+	flushes Level
+	gate    *gate // writes held entries together if set
+
+	mu      sync.Mutex
+	held    []heldEntry
+	dropped int
+	through bool // entries are written right away
+}
+
+type heldEntry struct {
+	level   Level
+	time    time.Time
+	ctx     context.Context
+	fields  Fields
+	message string
+}
+
+func (b *entryBuffer) add(e heldEntry) {
+	if !b.to.IsLevelEnabled(e.level) {
+		return
+	}
+	b.mu.Lock()
+	switch {
+	case b.through:
+		b.mu.Unlock()
+	case e.level <= b.flushes:
+		b.through = true
+		held, dropped := b.take()
+		b.mu.Unlock()
+		b.write(append(held, e), dropped)
+		return
+	case e.level >= b.holds:
+		if len(b.held) == maxBufferedEntries {
+			b.held = append(b.held[:0], b.held[1:]...)
+			b.dropped++
+		}
+		b.held = append(b.held, e)
+		b.mu.Unlock()
+		return
+	default:
+		b.mu.Unlock()
+	}
+	b.write([]heldEntry{e}, 0)
+}
+
+func (b *entryBuffer) end(write bool) {
+	b.mu.Lock()
+	b.through = true
+	held, dropped := b.take()
+	b.mu.Unlock()
+	if write {
+		b.write(held, dropped)
+	}
+}
+
+// take empties the buffer. Must be called with mu held.
+func (b *entryBuffer) take() ([]heldEntry, int) {
+	held, dropped := b.held, b.dropped
+	b.held, b.dropped = nil, 0
+	return held, dropped
+}
+
+func (b *entryBuffer) write(held []heldEntry, dropped int) {
+	if len(held) == 0 {
+		return
+	}
+	if dropped > 0 {
+		held = append([]heldEntry{{
+			level:   WarnLevel,
+			time:    held[0].time,
+			message: fmt.Sprintf("%d earlier entries did not fit the buffer and were dropped", dropped),
+		}}, held...)
+	}
+	if b.gate != nil {
+		b.gate.run(held)
+		return
+	}
+	for _, e := range held {
+		e.log(b.to)
+	}
+}
+
+// log logs e on to. Log does not exit on Fatal; the ExitFunc of the logger
+// e was held for does that.
+func (e heldEntry) log(to *logrus.Logger) {
+	to.WithFields(logrus.Fields(e.fields)).WithTime(e.time).WithContext(e.ctx).Log(e.level, e.message)
+}
+
+// bufferHook hands the entries of a logger derived from a buffered one to
+// the buffer, with fields added.
+type bufferHook struct {
+	buf    *entryBuffer
+	fields logrus.Fields
+}
+
+func (h bufferHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h bufferHook) Fire(entry *logrus.Entry) error {
+	data := make(Fields, len(h.fields)+len(entry.Data))
+	for k, v := range h.fields {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	h.buf.add(heldEntry{level: entry.Level, time: entry.Time, ctx: entry.Context, fields: data, message: entry.Message})
+	return nil
+}
+
+// bufferBackend is the backend of a buffered logger.
+type bufferBackend struct {
+	buf    *entryBuffer
+	fields Fields
+}
+
+func (b bufferBackend) enabled(level Level) bool {
+	return b.buf.to.IsLevelEnabled(level)
+}
+
+func (b bufferBackend) log(level Level, t time.Time, fields Fields, message string) {
+	if t.IsZero() {
+		t = time.Now()
+	}
+	data := make(Fields, len(b.fields)+len(fields))
+	for k, v := range b.fields {
+		data[k] = v
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+	b.buf.add(heldEntry{level: level, time: t, fields: data, message: message})
+}
+
+func (b bufferBackend) exit(code int) {
+	b.buf.to.Exit(code)
+}
+
+type bufferedKey struct{}
+
+// BufferRequests is HTTP middleware giving every request a buffered logger,
+// which handlers get with RequestLogger. It ends with an error, writing the
+// held entries, when the handler responds with a 5xx status or panics.
+//
+//	http.ListenAndServe(addr, log.BufferRequests(mux))
+func (l *OnyLogger) BufferRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b := l.Buffered()
+		sw := &statusWriter{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			if p := recover(); p != nil {
+				b.End(fmt.Errorf("panic: %v", p))
+				panic(p)
+			}
+			var err error
+			if sw.status >= http.StatusInternalServerError {
+				err = fmt.Errorf("%s %s: %s", r.Method, r.URL.Path, http.StatusText(sw.status))
+			}
+			b.End(err)
+		}()
+		next.ServeHTTP(sw, r.WithContext(context.WithValue(r.Context(), bufferedKey{}, b)))
+	})
+}
+
+// RequestLogger returns the buffered logger BufferRequests gave the request
+// ctx belongs to, or fallback outside of one.
+//
+//	log := onylogger.RequestLogger(r.Context(), log)
+func RequestLogger(ctx context.Context, fallback *OnyLogger) *OnyLogger {
+	if b, ok := ctx.Value(bufferedKey{}).(*BufferedLogger); ok {
+		return b.OnyLogger
+	}
+	return fallback
+}
+
+// statusWriter records the status of a response.
+type statusWriter struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+func (w *statusWriter) WriteHeader(status int) {
+	if !w.wroteHeader {
+		w.status, w.wroteHeader = status, true
+	}
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusWriter) Write(p []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(p)
+}
+
+// Unwrap gives http.ResponseController access to the underlying writer.
+func (w *statusWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,158 @@
+package onylogger
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cloudProbeTimeout bounds how long New waits for the metadata services,
+// which off the cloud usually means waiting for the connection to time out.
+const cloudProbeTimeout = 500 * time.Millisecond
+
+// WithCloudInfo adds cloud_provider, cloud_region, cloud_zone and
+// cloud_instance_id fields to every entry when running on EC2, GCE or
+// Azure, for correlating the logs of a fleet. The instance metadata
+// services are probed once per process, by the first New that asks, for at
+// most half a second. Like the WithServiceInfo fields, they show up in JSON
+// outputs only.
+func WithCloudInfo() Option {
+	return func(o *options) {
+		o.cloud = true
+	}
+}
+
+var (
+	cloudOnce   sync.Once
+	cloudFields Fields
+)
+
+// cloudInfo returns the instance metadata, or nil if no metadata service
+// answered.
+func cloudInfo() Fields {
+	cloudOnce.Do(func() {
+		ctx, cancel := context.WithTimeout(context.Background(), cloudProbeTimeout)
+		defer cancel()
+
+		// Metadata services are link-local: never go through a proxy.
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = nil
+		client := &http.Client{Transport: transport}
+
+		found := make(chan Fields, 3)
+		for _, probe := range []func(context.Context, *http.Client) (Fields, error){probeEC2, probeGCE, probeAzure} {
+			go func() {
+				fields, err := probe(ctx, client)
+				if err != nil {
+					fields = nil
+				}
+				found <- fields
+			}()
+		}
+		for range 3 {
+			if fields := <-found; fields != nil {
+				cloudFields = fields
+				return
+			}
+		}
+	})
+	return cloudFields
+}
+
+func probeEC2(ctx context.Context, client *http.Client) (Fields, error) {
+	// IMDSv2 wants a session token first.
+	token, err := metadataGet(ctx, client, http.MethodPut, "http://169.254.169.254/latest/api/token",
+		http.Header{"X-Aws-Ec2-Metadata-Token-Ttl-Seconds": {"60"}})
+	if err != nil {
+		return nil, err
+	}
+	body, err := metadataGet(ctx, client, http.MethodGet, "http://169.254.169.254/latest/dynamic/instance-identity/document",
+		http.Header{"X-Aws-Ec2-Metadata-Token": {token}})
+	if err != nil {
+		return nil, err
+	}
+	var doc struct {
+		Region           string `json:"region"`
+		AvailabilityZone string `json:"availabilityZone"`
+		InstanceID       string `json:"instanceId"`
+	}
+	if err := json.Unmarshal([]byte(body), &doc); err != nil {
+		return nil, err
+	}
+	return newCloudFields("aws", doc.Region, doc.AvailabilityZone, doc.InstanceID), nil
+}
+
+func probeGCE(ctx context.Context, client *http.Client) (Fields, error) {
+	header := http.Header{"Metadata-Flavor": {"Google"}}
+	zone, err := metadataGet(ctx, client, http.MethodGet, "http://metadata.google.internal/computeMetadata/v1/instance/zone", header)
+	if err != nil {
+		return nil, err
+	}
+	id, err := metadataGet(ctx, client, http.MethodGet, "http://metadata.google.internal/computeMetadata/v1/instance/id", header)
+	if err != nil {
+		return nil, err
+	}
+	// The zone comes as "projects/123/zones/europe-west1-b".
+	zone = zone[strings.LastIndex(zone, "/")+1:]
+	region := zone
+	if i := strings.LastIndex(zone, "-"); i > 0 {
+		region = zone[:i]
+	}
+	return newCloudFields("gcp", region, zone, id), nil
+}
+
+func probeAzure(ctx context.Context, client *http.Client) (Fields, error) {
+	body, err := metadataGet(ctx, client, http.MethodGet, "http://169.254.169.254/metadata/instance/compute?api-version=2021-02-01",
+		http.Header{"Metadata": {"true"}})
+	if err != nil {
+		return nil, err
+	}
+	var compute struct {
+		Location string `json:"location"`
+		Zone     string `json:"zone"`
+		VMID     string `json:"vmId"`
+	}
+	if err := json.Unmarshal([]byte(body), &compute); err != nil {
+		return nil, err
+	}
+	return newCloudFields("azure", compute.Location, compute.Zone, compute.VMID), nil
+}
+
+func newCloudFields(provider, region, zone, instanceID string) Fields {
+	fields := Fields{"cloud_provider": provider}
+	for k, v := range map[string]string{"cloud_region": region, "cloud_zone": zone, "cloud_instance_id": instanceID} {
+		if v != "" {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// metadataGet sends a request to a metadata service and returns the body
+// of its answer.
+func metadataGet(ctx context.Context, client *http.Client, method, url string, header http.Header) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, method, url, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header = header
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("%s: %s", url, resp.Status)
+	}
+	return strings.TrimSpace(string(body)), nil
+}
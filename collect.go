@@ -0,0 +1,76 @@
+package onylogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithErrorCollection keeps every Error entry in memory so that
+// ExitIfErrors can report them all at the end, e.g. in validators that
+// must list every problem before failing.
+func WithErrorCollection() Option {
+	return func(o *options) {
+		o.collectErrors = true
+	}
+}
+
+// CollectedError is an Error entry kept by WithErrorCollection.
+type CollectedError struct {
+	Time    time.Time
+	Message string
+	Fields  Fields
+}
+
+type errorCollector struct {
+	mu     sync.Mutex
+	errors []CollectedError
+}
+
+func (c *errorCollector) Levels() []logrus.Level {
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+}
+
+func (c *errorCollector) Fire(entry *logrus.Entry) error {
+	if demoted(entry, c.Levels()) {
+		return nil
+	}
+	e := newEntry(entry)
+
+	c.mu.Lock()
+	c.errors = append(c.errors, CollectedError{Time: e.Time, Message: e.Message, Fields: e.Fields})
+	c.mu.Unlock()
+	return nil
+}
+
+// CollectedErrors returns the Error entries collected so far. It is empty
+// unless the logger was created WithErrorCollection.
+func (l *OnyLogger) CollectedErrors() []CollectedError {
+	if l.collector == nil {
+		return nil
+	}
+	l.collector.mu.Lock()
+	defer l.collector.mu.Unlock()
+	return append([]CollectedError(nil), l.collector.errors...)
+}
+
+// ExitIfErrors does nothing if no errors were collected. Otherwise it logs
+// a summary listing every collected error and exits with code.
+func (l *OnyLogger) ExitIfErrors(code int) {
+	errs := l.CollectedErrors()
+	if len(errs) == 0 {
+		return
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "%d error(s) reported:", len(errs))
+	for i, e := range errs {
+		fmt.Fprintf(&report, "\n    %d. %s", i+1, e.Message)
+	}
+
+	l.emit(ErrorLevel, Fields{"error_count": len(errs)}, report.String())
+	l.backend.exit(code)
+}
@@ -0,0 +1,91 @@
+package onylogger
+
+import (
+	"fmt"
+	"strconv"
+)
+
+// ColorRule colors the console line of the entries it matches, whatever
+// their level.
+type ColorRule struct {
+	// Match reports whether the rule applies to an entry.
+	Match func(e Entry) bool
+	// Color is the ANSI escape for the line, e.g. "\033[31m", or
+	// "\033[30;43m" for black on yellow.
+	Color string
+}
+
+// WithColorRules adds rules that color console lines by their fields, to
+// make important lines stand out in dense output. The first matching rule
+// wins.
+//
+//	onylogger.WithColorRules(
+//		onylogger.ColorRule{Match: onylogger.FieldAtLeast("status", 500), Color: "\033[31m"},
+//		onylogger.ColorRule{Match: onylogger.FieldEquals("component", "payments"), Color: "\033[30;43m"},
+//	)
+func WithColorRules(rules ...ColorRule) Option {
+	return func(o *options) {
+		o.colorRules = append(o.colorRules, rules...)
+	}
+}
+
+// FieldEquals matches entries whose field key prints as value.
+func FieldEquals(key string, value interface{}) func(Entry) bool {
+	want := fmt.Sprint(value)
+	return func(e Entry) bool {
+		v, ok := e.Fields[key]
+		return ok && fmt.Sprint(v) == want
+	}
+}
+
+// FieldAtLeast matches entries whose field key is a number, or a string
+// holding one, of at least min.
+func FieldAtLeast(key string, min float64) func(Entry) bool {
+	return func(e Entry) bool {
+		n, ok := fieldNumber(e.Fields[key])
+		return ok && n >= min
+	}
+}
+
+func fieldNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case string:
+		n, err := strconv.ParseFloat(v, 64)
+		return n, err == nil
+	}
+	return 0, false
+}
+
+// ruleColor returns the color of the first rule matching e.
+func (f *emojiFormatter) ruleColor(e *Entry) (string, bool) {
+	for _, r := range f.colorRules {
+		if r.Match != nil && r.Match(*e) {
+			return r.Color, true
+		}
+	}
+	return "", false
+}
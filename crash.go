@@ -0,0 +1,114 @@
+package onylogger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultCrashEntries = 100
+
+// WithCrashReport makes HandleCrash write its reports to dir and keeps the
+// last recent entries in memory to include in them (100 if recent is 0).
+func WithCrashReport(dir string, recent int) Option {
+	return func(o *options) {
+		if recent <= 0 {
+			recent = defaultCrashEntries
+		}
+		o.crashDir = dir
+		o.recentEntries = max(o.recentEntries, recent)
+	}
+}
+
+// HandleCrash turns a panic into a crash report. Defer it at the top of main
+// and of long-lived goroutines:
+//
+//	defer log.HandleCrash()
+//
+// On panic it writes a timestamped report with the panic value, a dump of
+// all goroutines, the recent entries and the build info, then logs the
+// report's path at Fatal level, which exits. Without WithCrashReport the
+// report goes to the temp directory and has no recent entries.
+func (l *OnyLogger) HandleCrash() {
+	r := recover()
+	if r == nil {
+		return
+	}
+	path, err := l.writeCrashReport(r, time.Now())
+	if err != nil {
+		l.emitf(FatalLevel, Fields{"panic": fmt.Sprint(r)}, "Panic (failed to write crash report: %v)", err)
+		l.backend.exit(1)
+	}
+	l.emitf(FatalLevel, Fields{
+		"emoji":        "[💥] ",
+		"crash_report": path,
+	}, "Panic: %v (crash report written to %s)", r, path)
+	l.backend.exit(1)
+}
+
+func (l *OnyLogger) writeCrashReport(r interface{}, now time.Time) (string, error) {
+	dir := l.opts.crashDir
+	if dir == "" {
+		dir = os.TempDir()
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	info := readBuildInfo(StartupOptions{})
+	name := fmt.Sprintf("crash-%s-%s-%d.txt", info.name, now.Format(rotatedSuffixLayout), info.pid)
+	path := filepath.Join(dir, name)
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Crash report for %s %s\n", info.name, info.version)
+	fmt.Fprintf(&report, "time:    %s\n", now.Format(time.RFC3339Nano))
+	fmt.Fprintf(&report, "commit:  %s\n", info.commit)
+	fmt.Fprintf(&report, "go:      %s\n", info.goVersion)
+	fmt.Fprintf(&report, "pid:     %d\n", info.pid)
+	fmt.Fprintf(&report, "host:    %s\n", info.hostname)
+	fmt.Fprintf(&report, "\npanic: %v\n", r)
+
+	report.WriteString("\n--- goroutines ---\n\n")
+	report.Write(allStacks())
+
+	if l.recent != nil {
+		report.WriteString("\n--- recent entries ---\n\n")
+		for _, e := range l.recent.snapshot() {
+			writeCrashEntry(&report, e)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(report.String()), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// allStacks returns the stack traces of all goroutines.
+func allStacks() []byte {
+	buf := make([]byte, 64*1024)
+	for {
+		n := runtime.Stack(buf, true)
+		if n < len(buf) {
+			return buf[:n]
+		}
+		buf = make([]byte, 2*len(buf))
+	}
+}
+
+func writeCrashEntry(b *strings.Builder, e Entry) {
+	fmt.Fprintf(b, "%s %-7s %s", e.Time.Format(time.RFC3339Nano), strings.ToUpper(e.Level.String()), e.Message)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(b, " %s=%v", k, e.Fields[k])
+	}
+	b.WriteByte('\n')
+}
@@ -0,0 +1,96 @@
+package onylogger
+
+import (
+	"encoding/csv"
+	"io"
+	"sort"
+	"time"
+)
+
+// CSV columns for the entry itself; any other column names a field.
+const (
+	ColumnTime    = "time"
+	ColumnLevel   = "level"
+	ColumnMessage = "message"
+)
+
+// CSVWriter writes entries as CSV rows, one column per name given to
+// NewCSVWriter. Entries without a field leave its column empty.
+//
+//	w := onylogger.NewCSVWriter(os.Stdout, onylogger.ColumnTime, onylogger.ColumnLevel, "user_id", onylogger.ColumnMessage)
+//	for _, e := range log.Query(onylogger.Filter{Level: onylogger.ErrorLevel}) {
+//		w.Write(e)
+//	}
+//	w.Flush()
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []string
+	header  bool
+}
+
+// NewCSVWriter returns a writer that starts with a header row of columns.
+func NewCSVWriter(w io.Writer, columns ...string) *CSVWriter {
+	return &CSVWriter{w: csv.NewWriter(w), columns: columns}
+}
+
+// Write writes the row for e.
+func (c *CSVWriter) Write(e Entry) error {
+	if !c.header {
+		c.header = true
+		if err := c.w.Write(c.columns); err != nil {
+			return err
+		}
+	}
+	row := make([]string, len(c.columns))
+	for i, col := range c.columns {
+		switch col {
+		case ColumnTime:
+			row[i] = e.Time.Format(time.RFC3339Nano)
+		case ColumnLevel:
+			row[i] = e.Level.String()
+		case ColumnMessage:
+			row[i] = e.Message
+		default:
+			if v, ok := e.Fields[col]; ok {
+				row[i] = fieldString(v)
+			}
+		}
+	}
+	return c.w.Write(row)
+}
+
+// Flush writes out buffered rows and reports any error from writing.
+func (c *CSVWriter) Flush() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// WriteCSV writes entries as CSV. Without columns it writes the time, level
+// and message, followed by every field that occurs, sorted by name.
+func WriteCSV(w io.Writer, entries []Entry, columns ...string) error {
+	if len(columns) == 0 {
+		columns = defaultCSVColumns(entries)
+	}
+	c := NewCSVWriter(w, columns...)
+	for _, e := range entries {
+		if err := c.Write(e); err != nil {
+			return err
+		}
+	}
+	return c.Flush()
+}
+
+func defaultCSVColumns(entries []Entry) []string {
+	seen := make(map[string]bool)
+	var fields []string
+	for _, e := range entries {
+		for k := range e.Fields {
+			if !seen[k] {
+				seen[k] = true
+				fields = append(fields, k)
+			}
+		}
+	}
+	sort.Strings(fields)
+	return append([]string{ColumnTime, ColumnLevel, ColumnMessage}, fields...)
+}
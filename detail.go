@@ -0,0 +1,134 @@
+package onylogger
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// detailedEnv switches the console to the detailed format at startup when
+// set to a true value, e.g. ONYLOGGER_DETAILED=1.
+const detailedEnv = "ONYLOGGER_DETAILED"
+
+// SetDetailed switches the console between the compact format, one line per
+// entry, and the detailed one, which puts each field on a line of its own
+// under the message, followed by the caller and, for Error and worse, the
+// stack. It can be flipped at any time, e.g. from a key binding, without
+// touching the level; files and other sinks keep their format.
+func (l *OnyLogger) SetDetailed(detailed bool) {
+	l.detailed.Store(detailed)
+}
+
+// Detailed reports whether the console uses the detailed format.
+func (l *OnyLogger) Detailed() bool {
+	return l.detailed.Load()
+}
+
+// detailedFromEnv reads the startup value of the detailed format.
+func detailedFromEnv() bool {
+	on, _ := strconv.ParseBool(os.Getenv(detailedEnv))
+	return on
+}
+
+func (f *emojiFormatter) isDetailed() bool {
+	return f.detailed != nil && f.detailed.Load()
+}
+
+// writeDetails writes the fields, caller and stack of e one per line,
+// indented under the message.
+func (f *emojiFormatter) writeDetails(b *bytes.Buffer, e *Entry) {
+	// Unlike the compact format, which shows no fields unless asked to, the
+	// detailed one shows them all by default.
+	filter := f.fields
+	if !filter.isSet() {
+		filter = DenyFields()
+	}
+	for _, k := range f.fieldKeys(e, filter) {
+		v := e.Fields[k]
+		color := ""
+		if _, isErr := v.(error); isErr {
+			color = colorRed
+		}
+		f.writeDetail(b, f.text(k), f.text(fieldString(v)), color)
+	}
+
+	frame, ok := callerFrame(e)
+	if ok {
+		f.writeDetail(b, "caller", frame.Function+" "+frame.File+":"+strconv.Itoa(frame.Line), "")
+	}
+	if _, hasStack := e.Fields["stack"]; !hasStack && e.Level <= logrus.ErrorLevel {
+		f.writeDetail(b, "stack", callerStack(), colorDim)
+	}
+}
+
+func (f *emojiFormatter) writeDetail(b *bytes.Buffer, key, value, color string) {
+	b.WriteString("\n    ")
+	f.startColor(b, colorDim)
+	b.WriteString(key)
+	b.WriteByte(':')
+	f.endColor(b)
+
+	lines := strings.Split(strings.TrimRight(value, "\n"), "\n")
+	indent := " "
+	if len(lines) > 1 {
+		indent = "\n        "
+	}
+	for _, line := range lines {
+		b.WriteString(indent)
+		if color == "" {
+			b.WriteString(line)
+			continue
+		}
+		f.startColor(b, color)
+		b.WriteString(line)
+		f.endColor(b)
+	}
+}
+
+// isLoggerFrame reports whether function belongs to logrus or this package,
+// which sit between the caller and the formatter.
+func isLoggerFrame(function string) bool {
+	return strings.HasPrefix(function, "github.com/sirupsen/logrus.") ||
+		strings.HasPrefix(function, "github.com/Onyz107/onylogger.")
+}
+
+// callerFrame finds the code that logged e. Formatters run on the
+// logging goroutine, so unless logrus already reported the caller it is the
+// first frame on the stack outside the logger.
+func callerFrame(e *Entry) (runtime.Frame, bool) {
+	if e.Caller != nil {
+		return *e.Caller, true
+	}
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(2, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !isLoggerFrame(frame.Function) {
+			return frame, frame.Function != ""
+		}
+		if !more {
+			return runtime.Frame{}, false
+		}
+	}
+}
+
+// callerStack returns the stack of the logging goroutine without the
+// logger's own frames.
+func callerStack() string {
+	buf := make([]byte, 64<<10)
+	lines := strings.Split(string(buf[:runtime.Stack(buf, false)]), "\n")
+	// lines[0] is the goroutine header; frames follow as function/location
+	// pairs.
+	out := lines[:1]
+	for i := 1; i+1 < len(lines); i += 2 {
+		if isLoggerFrame(lines[i]) {
+			continue
+		}
+		out = append(out, lines[i], lines[i+1])
+	}
+	return strings.Join(out, "\n")
+}
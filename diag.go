@@ -0,0 +1,82 @@
+package onylogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+)
+
+// errorReportInterval is how often the default handler prints internal
+// errors; the ones in between are counted and mentioned with the next.
+const errorReportInterval = 10 * time.Second
+
+// SetErrorHandler sends the logger's own errors to fn: sink writes that
+// fail, entries that cannot be formatted, and background work such as
+// rotation, archiving or alerting that goes wrong. fn may be called from
+// any goroutine, and must not log through l at a level that could fail the
+// same way. A nil fn restores the default, which prints to stderr at most
+// once every 10 seconds.
+func (l *OnyLogger) SetErrorHandler(fn func(error)) {
+	l.diag.mu.Lock()
+	defer l.diag.mu.Unlock()
+	l.diag.handler = fn
+}
+
+// reportError hands an internal error to the error handler.
+func (l *OnyLogger) reportError(err error) {
+	l.diag.report(err)
+}
+
+type errorReporter struct {
+	render *renderer // the default handler writes around its widgets
+
+	mu         sync.Mutex
+	handler    func(error)
+	last       time.Time
+	suppressed int
+}
+
+func (r *errorReporter) report(err error) {
+	r.mu.Lock()
+	if h := r.handler; h != nil {
+		r.mu.Unlock()
+		h(err)
+		return
+	}
+	now := time.Now()
+	if !r.last.IsZero() && now.Sub(r.last) < errorReportInterval {
+		r.suppressed++
+		r.mu.Unlock()
+		return
+	}
+	suppressed := r.suppressed
+	r.last, r.suppressed = now, 0
+	r.mu.Unlock()
+
+	msg := fmt.Sprintf("onylogger: %v\n", err)
+	if suppressed > 0 {
+		msg = fmt.Sprintf("onylogger: %v (%d more errors since the last report)\n", err, suppressed)
+	}
+	if r.render != nil {
+		r.render.writeAside(os.Stderr, msg)
+		return
+	}
+	io.WriteString(os.Stderr, msg)
+}
+
+// reportingWriter reports the errors of out instead of returning them, so
+// logrus does not print its own message for every entry.
+type reportingWriter struct {
+	out    io.Writer
+	report func(error)
+	what   string
+}
+
+func (w reportingWriter) Write(p []byte) (int, error) {
+	if _, err := w.out.Write(p); err != nil {
+		w.report(fmt.Errorf("failed to write to %s: %w", w.what, err))
+	}
+	return len(p), nil
+}
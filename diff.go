@@ -0,0 +1,237 @@
+package onylogger
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// diffContext is the number of unchanged lines shown around changes.
+const diffContext = 3
+
+// maxDiffCells bounds the work of diffing two texts; larger texts are shown
+// as replaced wholesale.
+const maxDiffCells = 1 << 22
+
+// Diff logs at Info level what changed between old and new, for config
+// changes and reconciliation. Strings are compared line by line and shown
+// as a unified diff; structs and maps are compared field by field, nested
+// ones included. Other values are shown as replaced. The console colors
+// added and removed lines.
+//
+//	log.Diff("config reloaded", oldCfg, newCfg)
+func (l *OnyLogger) Diff(label string, oldVal, newVal interface{}) {
+	var lines []string
+	if oldText, ok := oldVal.(string); ok {
+		if newText, ok := newVal.(string); ok {
+			lines = diffText(oldText, newText)
+		}
+	}
+	if lines == nil {
+		lines = diffValues("", reflect.ValueOf(oldVal), reflect.ValueOf(newVal))
+	}
+	if len(lines) == 0 {
+		l.emitf(InfoLevel, nil, "%s: no changes", label)
+		return
+	}
+	l.emit(InfoLevel, Fields{"log_type": "diff"}, label+"\n"+strings.Join(lines, "\n"))
+}
+
+// diffText returns the unified diff of two texts, or nil if they are equal.
+func diffText(oldText, newText string) []string {
+	if oldText == newText {
+		return []string{}
+	}
+	a, b := strings.Split(oldText, "\n"), strings.Split(newText, "\n")
+	ops := diffLines(a, b)
+
+	out := []string{"--- old", "+++ new"}
+	for start := 0; start < len(ops); {
+		// Find the next change and the run of changes close enough to it to
+		// share a hunk.
+		for start < len(ops) && ops[start].kind == ' ' {
+			start++
+		}
+		if start == len(ops) {
+			break
+		}
+		end := start
+		for i := start; i < len(ops); i++ {
+			if ops[i].kind != ' ' {
+				end = i + 1
+			} else if i-end >= 2*diffContext {
+				break
+			}
+		}
+		from, to := max(start-diffContext, 0), min(end+diffContext, len(ops))
+
+		var oldStart, oldLen, newStart, newLen int
+		oldStart, newStart = ops[from].oldLine, ops[from].newLine
+		var hunk []string
+		for _, op := range ops[from:to] {
+			hunk = append(hunk, string(op.kind)+op.text)
+			if op.kind != '+' {
+				oldLen++
+			}
+			if op.kind != '-' {
+				newLen++
+			}
+		}
+		out = append(out, fmt.Sprintf("@@ -%d,%d +%d,%d @@", oldStart+1, oldLen, newStart+1, newLen))
+		out = append(out, hunk...)
+		start = to
+	}
+	return out
+}
+
+type diffOp struct {
+	kind             byte // ' ', '-' or '+'
+	text             string
+	oldLine, newLine int // position in each text before this line
+}
+
+// diffLines computes a line diff from the longest common subsequence.
+func diffLines(a, b []string) []diffOp {
+	var ops []diffOp
+	if len(a)*len(b) > maxDiffCells {
+		for i, s := range a {
+			ops = append(ops, diffOp{'-', s, i, 0})
+		}
+		for j, s := range b {
+			ops = append(ops, diffOp{'+', s, len(a), j})
+		}
+		return ops
+	}
+
+	// lcs[i][j] is the length of the LCS of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i], i, j})
+			i++
+			j++
+		case i < len(a) && j < len(b) && lcs[i+1][j+1] == lcs[i][j]:
+			// A changed line: pair the removal with its replacement.
+			ops = append(ops, diffOp{'-', a[i], i, j}, diffOp{'+', b[j], i + 1, j})
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] >= lcs[i+1][j]):
+			ops = append(ops, diffOp{'+', b[j], i, j})
+			j++
+		default:
+			ops = append(ops, diffOp{'-', a[i], i, j})
+			i++
+		}
+	}
+	return ops
+}
+
+// diffValues compares two values field by field, naming each difference by
+// its path, e.g. "-server.port: 80" and "+server.port: 8080".
+func diffValues(path string, a, b reflect.Value) []string {
+	for a.IsValid() && (a.Kind() == reflect.Pointer || a.Kind() == reflect.Interface) && !a.IsNil() {
+		a = a.Elem()
+	}
+	for b.IsValid() && (b.Kind() == reflect.Pointer || b.Kind() == reflect.Interface) && !b.IsNil() {
+		b = b.Elem()
+	}
+
+	switch {
+	case !a.IsValid() || !b.IsValid() || a.Type() != b.Type():
+	case a.Kind() == reflect.Struct:
+		var lines []string
+		for i := 0; i < a.NumField(); i++ {
+			if f := a.Type().Field(i); f.IsExported() {
+				lines = append(lines, diffValues(joinPath(path, f.Name), a.Field(i), b.Field(i))...)
+			}
+		}
+		return lines
+	case a.Kind() == reflect.Map:
+		keys := a.MapKeys()
+		for _, k := range b.MapKeys() {
+			if !a.MapIndex(k).IsValid() {
+				keys = append(keys, k)
+			}
+		}
+		sort.Slice(keys, func(i, j int) bool { return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j]) })
+		var lines []string
+		for _, k := range keys {
+			lines = append(lines, diffValues(joinPath(path, fmt.Sprint(k)), a.MapIndex(k), b.MapIndex(k))...)
+		}
+		return lines
+	case a.CanInterface() && reflect.DeepEqual(a.Interface(), b.Interface()):
+		return nil
+	}
+
+	var lines []string
+	if a.IsValid() {
+		lines = append(lines, "-"+diffLine(path, a))
+	}
+	if b.IsValid() {
+		lines = append(lines, "+"+diffLine(path, b))
+	}
+	return lines
+}
+
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+func diffLine(path string, v reflect.Value) string {
+	s := "<nil>"
+	if v.CanInterface() {
+		s = formatFieldValue(v.Interface())
+	}
+	if path == "" {
+		return s
+	}
+	return path + ": " + s
+}
+
+// writeDiff writes the lines of a diff message, colored on the console.
+func (f *emojiFormatter) writeDiff(b *bytes.Buffer, message string) {
+	for i, line := range strings.Split(message, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		color := ""
+		switch {
+		case i == 0:
+		case strings.HasPrefix(line, "+++"), strings.HasPrefix(line, "---"):
+			color = colorDim
+		case strings.HasPrefix(line, "+"):
+			color = colorGreen
+		case strings.HasPrefix(line, "-"):
+			color = colorRed
+		case strings.HasPrefix(line, "@@"):
+			color = colorCyan
+		}
+		if color == "" {
+			b.WriteString(line)
+			continue
+		}
+		f.startColor(b, color)
+		b.WriteString(line)
+		f.endColor(b)
+	}
+}
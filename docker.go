@@ -0,0 +1,50 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"time"
+)
+
+// dockerFormatter wraps the plain text format in the records of Docker's
+// json-file log driver, one per line of output:
+//
+//	{"log":"[2006-01-02 15:04:05] [📜] started\n","stream":"stderr","time":"2006-01-02T15:04:05.999999999Z"}
+//
+// The console is the stderr stream; other outputs are labeled stdout.
+type dockerFormatter struct {
+	text   *emojiFormatter
+	stream string
+}
+
+type dockerRecord struct {
+	Log    string `json:"log"`
+	Stream string `json:"stream"`
+	Time   string `json:"time"`
+}
+
+func (f *dockerFormatter) format(e *Entry, b *bytes.Buffer) ([]byte, error) {
+	// The text goes into a separate buffer, then b gets the records.
+	line, err := f.text.format(e, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	ts := e.Time.UTC().Format(time.RFC3339Nano)
+	for len(line) > 0 {
+		n := bytes.IndexByte(line, '\n') + 1
+		if n == 0 {
+			n = len(line)
+		}
+		if err := enc.Encode(dockerRecord{Log: string(line[:n]), Stream: f.stream, Time: ts}); err != nil {
+			return nil, err
+		}
+		line = line[n:]
+	}
+	return b.Bytes(), nil
+}
@@ -0,0 +1,34 @@
+package onylogger
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// OnDone logs, once ctx is done, how it ended and how long it ran: at Warn
+// level when its deadline passed and at Info level when it was canceled,
+// with the cancellation cause if one was given. Call the returned stop
+// function when the work finishes first, e.g. with defer, to log nothing.
+//
+//	ctx, cancel := context.WithTimeout(ctx, time.Minute)
+//	defer cancel()
+//	defer log.OnDone(ctx, "sync job")()
+func (l *OnyLogger) OnDone(ctx context.Context, name string) (stop func() bool) {
+	start := time.Now()
+	return context.AfterFunc(ctx, func() {
+		ran := time.Since(start)
+		fields := logrus.Fields{"duration": ran}
+		if cause := context.Cause(ctx); cause != nil && cause != ctx.Err() {
+			fields["cause"] = cause
+		}
+
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			l.emitf(WarnLevel, fields, "%s hit its deadline after %s", name, humanizeDuration(ran))
+			return
+		}
+		l.emitf(InfoLevel, fields, "%s was canceled after %s", name, humanizeDuration(ran))
+	})
+}
@@ -0,0 +1,66 @@
+package onylogger
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// InputEditor opens the user's editor ($VISUAL, then $EDITOR, then vi or
+// notepad) on a temporary file holding initialContent, and returns what
+// the file contains once the editor exits. The editor command may include
+// arguments, e.g. EDITOR="code --wait". When stdin is not a terminal the
+// content is read from stdin until its end instead.
+func (l *OnyLogger) InputEditor(message, initialContent string) (string, error) {
+	if !stdinIsTerminal() {
+		// Nobody to edit the file: take the content from stdin instead.
+		return l.InputMultiline(message, "")
+	}
+	l.logEmoji(logrus.InfoLevel, "[📝] ", message)
+	l.Flush()
+
+	f, err := os.CreateTemp("", "onylogger-*.txt")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.WriteString(initialContent); err != nil {
+		f.Close()
+		return "", err
+	}
+	if err := f.Close(); err != nil {
+		return "", err
+	}
+
+	args := strings.Fields(editorCommand())
+	if len(args) == 0 {
+		return "", errors.New("onylogger: no editor configured")
+	}
+	cmd := exec.Command(args[0], append(args[1:], f.Name())...)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return "", err
+	}
+
+	content, err := os.ReadFile(f.Name())
+	if err != nil {
+		return "", err
+	}
+	return string(content), nil
+}
+
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if cmd := os.Getenv(env); cmd != "" {
+			return cmd
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return "notepad"
+	}
+	return "vi"
+}
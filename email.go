@@ -0,0 +1,330 @@
+package onylogger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/smtp"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultEmailSubject = `[{{.Level}}] {{.Hostname}}: {{.Message}}`
+	defaultEmailBody    = `{{range .Entries}}[{{.Time.Format "2006-01-02 15:04:05"}}] {{.Level}}: {{.Message}}
+{{range $k, $v := .Fields}}    {{$k}}={{$v}}
+{{end}}{{end}}`
+)
+
+// emailTimeout bounds one delivery attempt, and how long a Panic entry
+// waits for its mail.
+const emailTimeout = 30 * time.Second
+
+// EmailConfig describes the SMTP sink added with WithEmail.
+type EmailConfig struct {
+	Addr string // host:port of the SMTP server
+	Auth smtp.Auth
+	From string
+	To   []string
+
+	// TLSConfig is used for STARTTLS, or for the whole connection when
+	// ImplicitTLS is set (SMTPS, usually port 465). Defaults to verifying
+	// against the server host name.
+	TLSConfig   *tls.Config
+	ImplicitTLS bool
+
+	// Subject and Body are text/template sources executed with an
+	// EmailMessage. Both have sensible defaults.
+	Subject string
+	Body    string
+
+	// ErrorBatchInterval, when non-zero, also mails Error entries: they are
+	// collected and sent as one message per interval.
+	ErrorBatchInterval time.Duration
+
+	// Retry decides whether failed deliveries are tried again; defaults to
+	// ExponentialBackoff.
+	Retry RetryPolicy
+}
+
+// EmailMessage is the data the subject and body templates are executed with.
+type EmailMessage struct {
+	Level    string // most severe level in Entries
+	Message  string // message of the first entry
+	Hostname string
+	Entries  []Entry
+}
+
+// WithEmail mails Fatal and Panic entries (and optionally batches of Error
+// entries) through an SMTP server. Messages are sent from a background
+// goroutine; Close waits for pending mail.
+func WithEmail(cfg EmailConfig) Option {
+	return func(o *options) {
+		o.emails = append(o.emails, cfg)
+	}
+}
+
+type emailSink struct {
+	cfg      EmailConfig
+	subject  *template.Template
+	body     *template.Template
+	hostname string
+	report   func(error)
+
+	queue chan emailJob
+	done  chan struct{}
+
+	mu        sync.Mutex
+	batch     []Entry
+	stop      chan struct{}
+	batchDone chan struct{} // closed when batchErrors returns
+
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+type emailJob struct {
+	entries []Entry
+	sent    chan struct{} // closed after delivery when non-nil
+}
+
+func (l *OnyLogger) addEmail(cfg EmailConfig) {
+	if cfg.Subject == "" {
+		cfg.Subject = defaultEmailSubject
+	}
+	if cfg.Body == "" {
+		cfg.Body = defaultEmailBody
+	}
+
+	subject, err := template.New("subject").Parse(cfg.Subject)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Invalid email subject template: %v", err)
+		return
+	}
+	body, err := template.New("body").Parse(cfg.Body)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Invalid email body template: %v", err)
+		return
+	}
+
+	s := &emailSink{
+		cfg:     cfg,
+		subject: subject,
+		body:    body,
+		queue:   make(chan emailJob, 16),
+		done:    make(chan struct{}),
+		stop:    make(chan struct{}),
+		report:  l.reportError,
+	}
+	s.hostname, _ = os.Hostname()
+
+	go s.run()
+	if cfg.ErrorBatchInterval > 0 {
+		s.batchDone = make(chan struct{})
+		go s.batchErrors()
+	}
+	l.closers = append(l.closers, s)
+	l.AddHook(s)
+}
+
+func (s *emailSink) Levels() []logrus.Level {
+	if s.cfg.ErrorBatchInterval > 0 {
+		return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel, logrus.ErrorLevel}
+	}
+	return []logrus.Level{logrus.PanicLevel, logrus.FatalLevel}
+}
+
+func (s *emailSink) Fire(entry *logrus.Entry) error {
+	s.closeMu.RLock()
+	defer s.closeMu.RUnlock()
+	if s.closed || demoted(entry, s.Levels()) {
+		return nil
+	}
+
+	e := newEntry(entry)
+
+	switch entry.Level {
+	case logrus.ErrorLevel:
+		s.mu.Lock()
+		s.batch = append(s.batch, e)
+		s.mu.Unlock()
+	case logrus.PanicLevel:
+		// The panic may well take the process down before the worker gets
+		// to it, so wait for this one, for as long as one attempt may take.
+		job := emailJob{entries: []Entry{e}, sent: make(chan struct{})}
+		s.queue <- job
+		timer := time.NewTimer(emailTimeout)
+		defer timer.Stop()
+		select {
+		case <-job.sent:
+		case <-timer.C:
+		}
+	default:
+		// Fatal: Close, which runs before the process exits, drains the queue.
+		s.queue <- emailJob{entries: []Entry{e}}
+	}
+	return nil
+}
+
+func (s *emailSink) batchErrors() {
+	defer close(s.batchDone)
+	ticker := time.NewTicker(s.cfg.ErrorBatchInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.flushBatch()
+		}
+	}
+}
+
+func (s *emailSink) flushBatch() {
+	s.mu.Lock()
+	batch := s.batch
+	s.batch = nil
+	s.mu.Unlock()
+
+	if len(batch) > 0 {
+		s.queue <- emailJob{entries: batch}
+	}
+}
+
+func (s *emailSink) run() {
+	defer close(s.done)
+	for job := range s.queue {
+		if err := deliver(s.cfg.Retry, func() error { return s.send(job.entries) }); err != nil {
+			s.report(fmt.Errorf("failed to send log email: %w", err))
+		}
+		if job.sent != nil {
+			close(job.sent)
+		}
+	}
+}
+
+// Close sends any batched errors and waits for queued mail to go out.
+func (s *emailSink) Close() error {
+	s.closeMu.Lock()
+	defer s.closeMu.Unlock()
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+
+	close(s.stop)
+	if s.batchDone != nil {
+		<-s.batchDone
+	}
+	s.flushBatch()
+	close(s.queue)
+	<-s.done
+	return nil
+}
+
+func (s *emailSink) send(entries []Entry) error {
+	level := entries[0].Level
+	for _, e := range entries {
+		level = min(level, e.Level)
+	}
+	msg := EmailMessage{
+		Level:    level.String(),
+		Message:  entries[0].Message,
+		Hostname: s.hostname,
+		Entries:  entries,
+	}
+
+	var subject, body bytes.Buffer
+	if err := s.subject.Execute(&subject, msg); err != nil {
+		return err
+	}
+	if err := s.body.Execute(&body, msg); err != nil {
+		return err
+	}
+	return s.deliver(buildEmail(s.cfg.From, s.cfg.To, subject.String(), body.String()))
+}
+
+func buildEmail(from string, to []string, subject, body string) []byte {
+	// Header values must stay on one line.
+	subject = strings.NewReplacer("\r", " ", "\n", " ").Replace(subject)
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", from)
+	fmt.Fprintf(&b, "To: %s\r\n", strings.Join(to, ", "))
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject)
+	fmt.Fprintf(&b, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(body, "\n", "\r\n"))
+	return b.Bytes()
+}
+
+func (s *emailSink) deliver(msg []byte) error {
+	host, _, err := net.SplitHostPort(s.cfg.Addr)
+	if err != nil {
+		return err
+	}
+	tlsConfig := s.cfg.TLSConfig
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{ServerName: host}
+	}
+
+	var conn net.Conn
+	dialer := &net.Dialer{Timeout: emailTimeout}
+	if s.cfg.ImplicitTLS {
+		conn, err = tls.DialWithDialer(dialer, "tcp", s.cfg.Addr, tlsConfig)
+	} else {
+		conn, err = dialer.Dial("tcp", s.cfg.Addr)
+	}
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(time.Now().Add(emailTimeout))
+
+	c, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer c.Close()
+
+	if !s.cfg.ImplicitTLS {
+		if ok, _ := c.Extension("STARTTLS"); ok {
+			if err := c.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if s.cfg.Auth != nil {
+		if err := c.Auth(s.cfg.Auth); err != nil {
+			return err
+		}
+	}
+	if err := c.Mail(s.cfg.From); err != nil {
+		return err
+	}
+	for _, to := range s.cfg.To {
+		if err := c.Rcpt(to); err != nil {
+			return err
+		}
+	}
+
+	w, err := c.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return c.Quit()
+}
@@ -0,0 +1,57 @@
+package onylogger
+
+import "github.com/sirupsen/logrus"
+
+// Logfe logs a printf-style message at level with a custom emoji in place
+// of the level's default one:
+//
+//	log.Infofe("[🚚] ", "Shipped %d orders", n)
+func (l *OnyLogger) Logfe(level Level, emoji, format string, args ...interface{}) {
+	if l.enabled(level) {
+		l.emitf(level, Fields{"emoji": emoji}, format, args...)
+	}
+}
+
+// Tracefe is Tracef with a custom emoji.
+func (l *OnyLogger) Tracefe(emoji, format string, args ...interface{}) {
+	l.Logfe(logrus.TraceLevel, emoji, format, args...)
+}
+
+// Debugfe is Debugf with a custom emoji.
+func (l *OnyLogger) Debugfe(emoji, format string, args ...interface{}) {
+	l.Logfe(logrus.DebugLevel, emoji, format, args...)
+}
+
+// Infofe is Infof with a custom emoji.
+func (l *OnyLogger) Infofe(emoji, format string, args ...interface{}) {
+	l.Logfe(logrus.InfoLevel, emoji, format, args...)
+}
+
+// Warnfe is Warnf with a custom emoji.
+func (l *OnyLogger) Warnfe(emoji, format string, args ...interface{}) {
+	l.Logfe(logrus.WarnLevel, emoji, format, args...)
+}
+
+// Errorfe is Errorf with a custom emoji.
+func (l *OnyLogger) Errorfe(emoji, format string, args ...interface{}) {
+	l.Logfe(logrus.ErrorLevel, emoji, format, args...)
+}
+
+// Fatalfe is Fatalf with a custom emoji; it exits like Fatalf.
+func (l *OnyLogger) Fatalfe(emoji, format string, args ...interface{}) {
+	l.emitf(FatalLevel, Fields{"emoji": emoji}, format, args...)
+	l.backend.exit(1)
+}
+
+// Panicfe is Panicf with a custom emoji; it panics like Panicf.
+func (l *OnyLogger) Panicfe(emoji, format string, args ...interface{}) {
+	l.emitf(PanicLevel, Fields{"emoji": emoji}, format, args...)
+}
+
+// logEmoji logs message at level with a custom emoji. The level check comes
+// first so a disabled level does not pay for building the entry.
+func (l *OnyLogger) logEmoji(level logrus.Level, emoji, message string) {
+	if l.enabled(level) {
+		l.emit(level, Fields{"emoji": emoji}, message)
+	}
+}
@@ -0,0 +1,105 @@
+package onylogger
+
+import (
+	"bytes"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Level is the severity of an entry.
+type Level = logrus.Level
+
+// The levels, from the most to the least severe.
+const (
+	PanicLevel = logrus.PanicLevel
+	FatalLevel = logrus.FatalLevel
+	ErrorLevel = logrus.ErrorLevel
+	WarnLevel  = logrus.WarnLevel
+	InfoLevel  = logrus.InfoLevel
+	DebugLevel = logrus.DebugLevel
+	TraceLevel = logrus.TraceLevel
+)
+
+// Fields are the key/value pairs attached to an entry.
+type Fields = logrus.Fields
+
+// Entry is a logged entry as formatters and sinks see it, and a snapshot
+// safe to keep after the logging call returns. Fields excludes the
+// package's internal control fields.
+type Entry struct {
+	Time    time.Time
+	Level   Level
+	Message string
+	Fields  Fields
+	// Caller is the code that logged the entry, when the logger reports it
+	// (see logrus's SetReportCaller).
+	Caller *runtime.Frame
+	// Stack is the stack trace logged with the entry in its "stack" field,
+	// e.g. by WarnIfSlow, if any.
+	Stack string
+
+	// How the text format presents the entry, from the control fields.
+	emoji     string
+	hasEmoji  bool
+	logType   string // "input", "diff" or "json"
+	noNewline bool
+	custom    *CustomLevel
+}
+
+// entryFrom converts a logrus entry for formatting, keeping its message as
+// logged.
+func entryFrom(e *logrus.Entry) Entry {
+	entry := Entry{Time: e.Time, Level: e.Level, Message: e.Message, Caller: e.Caller}
+	entry.Fields = make(Fields, len(e.Data))
+	for k, v := range e.Data {
+		switch k {
+		case "emoji":
+			entry.emoji, entry.hasEmoji = v.(string)
+		case "log_type":
+			entry.logType, _ = v.(string)
+		case "no_newline":
+			entry.noNewline, _ = v.(bool)
+		case "custom_level":
+			entry.custom, _ = v.(*CustomLevel)
+		default:
+			entry.Fields[k] = v
+		}
+	}
+	entry.Stack, _ = entry.Fields["stack"].(string)
+	return entry
+}
+
+// newEntry snapshots a logrus entry, with hyperlinks spelled out.
+func newEntry(e *logrus.Entry) Entry {
+	entry := entryFrom(e)
+	entry.Message = plainLinks(entry.Message)
+	return entry
+}
+
+// entryFormatter renders entries for one output. b, when not nil, is a
+// buffer to render into.
+type entryFormatter interface {
+	format(e *Entry, b *bytes.Buffer) ([]byte, error)
+}
+
+// logrusFormatter hands the entries logrus writes to an entryFormatter.
+type logrusFormatter struct {
+	f      entryFormatter
+	report func(error)
+}
+
+func (l logrusFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	if demoted(entry, logrus.AllLevels) {
+		return nil, nil
+	}
+	e := entryFrom(entry)
+	b, err := safeFormat(l.f, &e, entry.Buffer)
+	if err != nil && l.report != nil {
+		l.report(fmt.Errorf("failed to format an entry for the console: %w", err))
+		return nil, nil
+	}
+	return b, err
+}
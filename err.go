@@ -0,0 +1,130 @@
+package onylogger
+
+import (
+	"io"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Err returns a logger that adds err to every entry in the "error" field.
+// Unlike WithError, which returns a *logrus.Entry, it keeps all of
+// OnyLogger's methods:
+//
+//	log.Err(err).Warnfe("[🔁] ", "Retrying upload of %s", name)
+//
+// The returned logger writes through l and shares its outputs; closing it
+// closes l.
+func (l *OnyLogger) Err(err error) *OnyLogger {
+	return l.withFields(logrus.Fields{logrus.ErrorKey: err})
+}
+
+// withFields derives a logger adding fields to every entry. Its own
+// logrus.Logger only hands entries on to the root logger, so levels, hooks
+// and outputs stay those of the root.
+func (l *OnyLogger) withFields(fields logrus.Fields) *OnyLogger {
+	return l.derive(fields, l.buffer)
+}
+
+// derive is withFields, with the entries handed to buffer if it is set
+// (see Buffered).
+func (l *OnyLogger) derive(fields logrus.Fields, buffer *entryBuffer) *OnyLogger {
+	root := l
+	if l.root != nil {
+		root = l.root
+	}
+	merged := make(logrus.Fields, len(l.fields)+len(fields))
+	for k, v := range l.fields {
+		merged[k] = v
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	log := logrus.New()
+	log.SetOutput(io.Discard)
+	log.SetFormatter(discardFormatter{})
+	log.SetLevel(logrus.TraceLevel)
+	var b backend = fieldsBackend{to: root.backend, fields: merged}
+	if buffer != nil {
+		log.AddHook(bufferHook{buf: buffer, fields: merged})
+		b = bufferBackend{buf: buffer, fields: merged}
+	} else {
+		log.AddHook(forwardHook{to: root.Logger, fields: merged})
+	}
+	log.ExitFunc = func(code int) { root.Exit(code) }
+
+	return &OnyLogger{
+		Logger:    log,
+		backend:   b,
+		opts:      root.opts,
+		render:    root.render,
+		stats:     root.stats,
+		collector: root.collector,
+		recent:    root.recent,
+		async:     root.async,
+		files:     root.files,
+		flushers:  root.flushers,
+		detailed:  root.detailed,
+		diag:      root.diag,
+		sampler:   root.sampler,
+		gate:      root.gate,
+		root:      root,
+		fields:    merged,
+		buffer:    buffer,
+	}
+}
+
+// fieldsBackend logs through another backend with fields added.
+type fieldsBackend struct {
+	to     backend
+	fields Fields
+}
+
+func (b fieldsBackend) enabled(level Level) bool {
+	return b.to.enabled(level)
+}
+
+func (b fieldsBackend) log(level Level, t time.Time, fields Fields, message string) {
+	data := make(Fields, len(b.fields)+len(fields))
+	for k, v := range b.fields {
+		data[k] = v
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+	b.to.log(level, t, data, message)
+}
+
+func (b fieldsBackend) exit(code int) {
+	b.to.exit(code)
+}
+
+// forwardHook logs every entry again on another logger, with fields added.
+type forwardHook struct {
+	to     *logrus.Logger
+	fields logrus.Fields
+}
+
+func (h forwardHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h forwardHook) Fire(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(h.fields)+len(entry.Data))
+	for k, v := range h.fields {
+		data[k] = v
+	}
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	// Log does not exit on Fatal; the derived logger's ExitFunc does that.
+	h.to.WithFields(data).WithTime(entry.Time).WithContext(entry.Context).Log(entry.Level, entry.Message)
+	return nil
+}
+
+type discardFormatter struct{}
+
+func (discardFormatter) Format(*logrus.Entry) ([]byte, error) {
+	return nil, nil
+}
@@ -0,0 +1,142 @@
+package onylogger
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/sirupsen/logrus"
+)
+
+// An event schema is the struct type registered for an event name. Its
+// exported fields become the entry's fields, named by their `log` tag, their
+// `json` tag, or else the snake_cased field name; a tag of "-" skips a field.
+type eventSchema struct {
+	typ    reflect.Type
+	fields []eventField
+}
+
+type eventField struct {
+	index []int
+	key   string
+}
+
+var eventSchemas sync.Map // event name -> *eventSchema
+
+// EventMessage can be implemented by events to control the console line;
+// by default it is the event name followed by its fields.
+type EventMessage interface {
+	EventMessage() string
+}
+
+// RegisterEvent registers schema, a struct value, as the shape of the event
+// called name:
+//
+//	type UserLogin struct {
+//		UserID int    `log:"user_id"`
+//		IP     string `log:"ip"`
+//	}
+//
+//	onylogger.RegisterEvent("user.login", UserLogin{})
+//
+// It panics if schema is not a struct or name is already registered with a
+// different type.
+func RegisterEvent(name string, schema interface{}) {
+	typ := reflect.TypeOf(schema)
+	if typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if typ == nil || typ.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("onylogger: event %s: schema must be a struct, got %T", name, schema))
+	}
+
+	s := &eventSchema{typ: typ}
+	for _, f := range reflect.VisibleFields(typ) {
+		if !f.IsExported() || f.Anonymous {
+			continue
+		}
+		key := eventFieldKey(f)
+		if key == "-" {
+			continue
+		}
+		s.fields = append(s.fields, eventField{index: f.Index, key: key})
+	}
+
+	if prev, loaded := eventSchemas.LoadOrStore(name, s); loaded && prev.(*eventSchema).typ != typ {
+		panic(fmt.Sprintf("onylogger: event %s already registered as %s", name, prev.(*eventSchema).typ))
+	}
+}
+
+func eventFieldKey(f reflect.StructField) string {
+	for _, tag := range []string{"log", "json"} {
+		if name, _, _ := strings.Cut(f.Tag.Get(tag), ","); name != "" {
+			return name
+		}
+	}
+	return snakeCase(f.Name)
+}
+
+// snakeCase turns "UserID" into "user_id".
+func snakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			prevLower := i > 0 && !unicode.IsUpper(runes[i-1])
+			nextLower := i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if i > 0 && (prevLower || nextLower) && runes[i-1] != '_' {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// Event logs event, whose type must be registered for name with
+// RegisterEvent, at Info level. The entry has an "event" field with the
+// name and one field per schema field, so outputs get the same shape for
+// every occurrence.
+func (l *OnyLogger) Event(name string, event interface{}) error {
+	v, ok := eventSchemas.Load(name)
+	if !ok {
+		return fmt.Errorf("onylogger: event %s is not registered", name)
+	}
+	s := v.(*eventSchema)
+
+	rv := reflect.ValueOf(event)
+	if rv.Kind() == reflect.Pointer && !rv.IsNil() {
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() || rv.Type() != s.typ {
+		return fmt.Errorf("onylogger: event %s must be a %s, got %T", name, s.typ, event)
+	}
+	if !l.enabled(InfoLevel) {
+		return nil
+	}
+
+	fields := make(logrus.Fields, len(s.fields)+2)
+	for _, f := range s.fields {
+		fields[f.key] = rv.FieldByIndex(f.index).Interface()
+	}
+
+	var msg string
+	if m, ok := event.(EventMessage); ok {
+		msg = m.EventMessage()
+	} else {
+		var b strings.Builder
+		b.WriteString(name)
+		for _, f := range s.fields {
+			fmt.Fprintf(&b, " %s=%s", f.key, formatFieldValue(fields[f.key]))
+		}
+		msg = b.String()
+	}
+	fields["event"] = name
+	fields["emoji"] = "[📌] "
+
+	l.emit(InfoLevel, fields, msg)
+	return nil
+}
@@ -0,0 +1,37 @@
+package onylogger
+
+import "github.com/sirupsen/logrus"
+
+var defaultExitCodes = map[logrus.Level]int{
+	logrus.WarnLevel:  1,
+	logrus.ErrorLevel: 2,
+	logrus.FatalLevel: 2,
+	logrus.PanicLevel: 2,
+}
+
+// WithExitCodes replaces the level to exit code mapping used by ExitCode.
+// Levels missing from the map count as 0.
+func WithExitCodes(codes map[Level]int) Option {
+	return func(o *options) {
+		o.exitCodes = codes
+	}
+}
+
+// ExitCode translates the most severe level logged so far into a process
+// exit status: by default 0 if nothing above Info was logged, 1 after
+// warnings and 2 after errors.
+//
+//	os.Exit(log.ExitCode())
+func (l *OnyLogger) ExitCode() int {
+	codes := l.opts.exitCodes
+	if codes == nil {
+		codes = defaultExitCodes
+	}
+
+	for _, level := range logrus.AllLevels {
+		if l.stats.counts[level].Load() > 0 {
+			return codes[level]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,202 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// FieldFilter limits which fields an output writes. The zero value keeps
+// each output's default: JSON outputs write every field, text outputs none.
+type FieldFilter struct {
+	keys  map[string]bool
+	allow bool
+}
+
+// AllowFields makes an output write only the listed fields.
+func AllowFields(keys ...string) FieldFilter {
+	return FieldFilter{keys: keySet(keys), allow: true}
+}
+
+// DenyFields makes an output write every field except the listed ones.
+// Text outputs still leave out the WithServiceInfo fields unless they are
+// allowed explicitly.
+func DenyFields(keys ...string) FieldFilter {
+	return FieldFilter{keys: keySet(keys)}
+}
+
+func keySet(keys []string) map[string]bool {
+	set := make(map[string]bool, len(keys))
+	for _, k := range keys {
+		set[k] = true
+	}
+	return set
+}
+
+func (f FieldFilter) isSet() bool {
+	return f.keys != nil
+}
+
+func (f FieldFilter) keep(key string) bool {
+	if !f.isSet() {
+		return true
+	}
+	return f.keys[key] == f.allow
+}
+
+// WithConsoleFields selects the fields shown on the console, for example
+// AllowFields("component", "duration") to keep the terminal readable while
+// a JSON file sink keeps everything.
+func WithConsoleFields(filter FieldFilter) Option {
+	return func(o *options) {
+		o.fields = filter
+	}
+}
+
+// FileFields selects the fields a file sink writes.
+func FileFields(filter FieldFilter) FileOption {
+	return func(fc *fileConfig) {
+		fc.fields = filter
+	}
+}
+
+// SocketFields selects the fields a socket sink writes.
+func SocketFields(filter FieldFilter) SocketOption {
+	return func(sc *socketConfig) {
+		sc.fields = filter
+	}
+}
+
+// serviceFields are the WithServiceInfo, WithKubernetesInfo and
+// WithCloudInfo fields, meant for log ingestion rather than for reading.
+var serviceFields = map[string]bool{
+	"service": true, "env": true, "hostname": true, "version": true,
+	"k8s_namespace": true, "k8s_pod": true, "k8s_node": true, "k8s_container": true,
+	"cloud_provider": true, "cloud_region": true, "cloud_zone": true, "cloud_instance_id": true,
+}
+
+// textFields returns the keys of the fields the text formatter shows after
+// the message, in the order they are written.
+func (f *emojiFormatter) textFields(e *Entry) []string {
+	if !f.fields.isSet() {
+		return nil
+	}
+	return f.fieldKeys(e, f.fields)
+}
+
+// fieldKeys returns the keys of the fields filter keeps, in order.
+func (f *emojiFormatter) fieldKeys(e *Entry, filter FieldFilter) []string {
+	var keys []string
+	for k := range e.Fields {
+		if f.isDimField(k) || !filter.keep(k) {
+			continue
+		}
+		if serviceFields[k] && !filter.allow {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	f.order.sort(keys)
+	return keys
+}
+
+func (f *emojiFormatter) isDimField(key string) bool {
+	for _, k := range f.dimFields {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *emojiFormatter) writeFields(b *bytes.Buffer, e *Entry) {
+	for _, k := range f.textFields(e) {
+		b.WriteByte(' ')
+		f.startColor(b, colorDim)
+		b.WriteString(f.text(k))
+		b.WriteByte('=')
+		f.endColor(b)
+
+		v := e.Fields[k]
+		if _, isErr := v.(error); isErr {
+			f.startColor(b, colorRed)
+			b.WriteString(f.fieldValue(v))
+			f.endColor(b)
+			continue
+		}
+		b.WriteString(f.fieldValue(v))
+	}
+}
+
+// fieldValue is formatFieldValue with control characters escaped, quoting
+// the value so that it reads back the same.
+func (f *emojiFormatter) fieldValue(v interface{}) string {
+	s := formatFieldValue(v)
+	if !f.rawControl && sanitize(s) != s {
+		return strconv.Quote(fieldString(v))
+	}
+	return s
+}
+
+// formatFieldValue renders a field value for text output, quoting it when
+// it would otherwise be ambiguous. Errors, fmt.Stringers and
+// encoding.TextMarshalers are shown through those interfaces.
+func formatFieldValue(v interface{}) string {
+	s := fieldString(v)
+	if s == "" || strings.ContainsAny(s, " \t\r\n\"=") {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+func fieldString(v interface{}) string {
+	switch v := v.(type) {
+	case []byte:
+		return bytesString(v)
+	case time.Duration:
+		return humanizeDuration(v)
+	case error, fmt.Stringer:
+		return fmt.Sprint(v) // fmt also recovers from panics in nil receivers
+	case encoding.TextMarshaler:
+		return marshalText(v)
+	}
+	return fmt.Sprint(v)
+}
+
+// maxBytesPreview is how much of a binary []byte field is shown.
+const maxBytesPreview = 32
+
+// bytesString renders a []byte field: text as a string, anything else as a
+// hex preview with its length, "89504e470d0a1a0a (8 bytes)".
+func bytesString(b []byte) string {
+	if utf8.Valid(b) && !bytes.ContainsFunc(b, isBinaryRune) {
+		return string(b)
+	}
+	preview := hex.EncodeToString(b[:min(len(b), maxBytesPreview)])
+	if len(b) > maxBytesPreview {
+		preview += "…"
+	}
+	return fmt.Sprintf("%s (%d bytes)", preview, len(b))
+}
+
+func isBinaryRune(r rune) bool {
+	return r < 0x20 && r != '\n' && r != '\r' && r != '\t' || r == 0x7f
+}
+
+func marshalText(v encoding.TextMarshaler) (s string) {
+	defer func() {
+		if r := recover(); r != nil {
+			s = fmt.Sprintf("<PANIC=%v>", r)
+		}
+	}()
+	text, err := v.MarshalText()
+	if err != nil {
+		return fmt.Sprintf("<ERROR=%v>", err)
+	}
+	return string(text)
+}
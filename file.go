@@ -0,0 +1,231 @@
+package onylogger
+
+import (
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	defaultFileBufferSize    = 32 * 1024
+	defaultFileFlushInterval = time.Second
+)
+
+// FileOption configures a file sink added with WithFile.
+type FileOption func(*fileConfig)
+
+type fileConfig struct {
+	path          string
+	bufferSize    int
+	flushInterval time.Duration
+	style         Style
+	format        Format
+	loggerFormat  bool // use the logger's format instead
+	fields        FieldFilter
+	location      *time.Location
+
+	fileMode os.FileMode
+	dirMode  os.FileMode
+	uid, gid int
+
+	maxSize      int64
+	maxFiles     int
+	maxTotalSize int64
+	compress     bool
+	shared       bool
+	lock         bool
+
+	queue *QueueConfig
+}
+
+// WithFile additionally writes every entry, without colors, to the file at
+// path. The file is opened for appending and created, along with any
+// missing parent directories, if it does not exist.
+func WithFile(path string, opts ...FileOption) Option {
+	return func(o *options) {
+		fc := &fileConfig{
+			path:          path,
+			bufferSize:    defaultFileBufferSize,
+			flushInterval: defaultFileFlushInterval,
+			fileMode:      0o644,
+			dirMode:       0o755,
+			uid:           -1,
+			gid:           -1,
+		}
+		for _, opt := range opts {
+			opt(fc)
+		}
+		o.files = append(o.files, fc)
+	}
+}
+
+// FileBuffer sets how many bytes are collected before they are written to
+// the file, and how often a partially filled buffer is flushed anyway. A size
+// of 0 writes every entry immediately.
+func FileBuffer(size int, flushInterval time.Duration) FileOption {
+	return func(fc *fileConfig) {
+		fc.bufferSize = size
+		fc.flushInterval = flushInterval
+	}
+}
+
+// FileQueue sets how entries are queued for the file, overriding WithAsync.
+func FileQueue(q QueueConfig) FileOption {
+	return func(fc *fileConfig) {
+		fc.queue = &q
+	}
+}
+
+// FilePerm sets the permissions new log files and created parent
+// directories get (before the umask). The defaults are 0644 and 0755.
+func FilePerm(file, dir os.FileMode) FileOption {
+	return func(fc *fileConfig) {
+		fc.fileMode = file
+		fc.dirMode = dir
+	}
+}
+
+// FileOwner changes the owner of the log file, and of every file started on
+// rotation, to uid and gid; -1 leaves either unchanged. This is only
+// supported on unix, and usually needs root.
+func FileOwner(uid, gid int) FileOption {
+	return func(fc *fileConfig) {
+		fc.uid = uid
+		fc.gid = gid
+	}
+}
+
+func (l *OnyLogger) addFile(fc *fileConfig, o *options) {
+	f, err := openLogFile(fc, l.reportError)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Failed to open log file %s: %v", fc.path, err)
+		return
+	}
+	l.files = append(l.files, f)
+	l.closers = append(l.closers, f)
+
+	var out io.Writer = f
+	var flushers []flusher
+	if fc.bufferSize > 0 {
+		buffered := newBufferedWriter(f, fc.bufferSize, fc.flushInterval)
+		l.flushers = append(l.flushers, buffered)
+		l.closers = append(l.closers, buffered)
+		flushers = append(flushers, buffered)
+		out = buffered
+	}
+
+	format := fc.format
+	if fc.loggerFormat {
+		format = o.format
+	}
+	formatter := o.newFormatter(format, fc.style, fc.fields, false)
+	if fc.location != nil {
+		setTimezone(formatter, fc.location)
+	}
+	s := l.addSink("log file "+fc.path, out, formatter, o.queue(fc.queue))
+	if w, ok := s.out.(*asyncWriter); ok {
+		flushers = append([]flusher{w}, flushers...)
+	}
+	s.flushOn = func(entry *logrus.Entry) bool {
+		return entry.Level <= logrus.ErrorLevel
+	}
+	s.flush = func() error {
+		for _, f := range flushers {
+			if err := f.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// bufferedWriter collects writes in memory and hands them to out when the
+// buffer fills up, on every flush interval, and on Flush/Close. Unlike
+// bufio it never splits a write: out only ever sees whole entries, which
+// keeps rotation and shared files from cutting records in half.
+type bufferedWriter struct {
+	mu   sync.Mutex
+	out  io.Writer
+	buf  []byte
+	size int
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func newBufferedWriter(out io.Writer, size int, flushInterval time.Duration) *bufferedWriter {
+	b := &bufferedWriter{
+		out:  out,
+		buf:  make([]byte, 0, size),
+		size: size,
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	if flushInterval > 0 {
+		go b.run(flushInterval)
+	} else {
+		close(b.done)
+	}
+	return b
+}
+
+func (b *bufferedWriter) run(flushInterval time.Duration) {
+	defer close(b.done)
+
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+			b.Flush()
+		}
+	}
+}
+
+func (b *bufferedWriter) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if len(b.buf)+len(p) > b.size {
+		if err := b.flush(); err != nil {
+			return 0, err
+		}
+	}
+	if len(p) >= b.size {
+		return b.out.Write(p)
+	}
+	b.buf = append(b.buf, p...)
+	return len(p), nil
+}
+
+func (b *bufferedWriter) Flush() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.flush()
+}
+
+// flush must be called with mu held.
+func (b *bufferedWriter) flush() error {
+	if len(b.buf) == 0 {
+		return nil
+	}
+	_, err := b.out.Write(b.buf)
+	b.buf = b.buf[:0]
+	return err
+}
+
+func (b *bufferedWriter) Close() error {
+	select {
+	case <-b.stop:
+	default:
+		close(b.stop)
+	}
+	<-b.done
+	return b.Flush()
+}
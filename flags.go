@@ -0,0 +1,123 @@
+package onylogger
+
+import (
+	"flag"
+	"fmt"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/pflag"
+)
+
+// cliFlags holds the values of the logging flags registered by BindFlags.
+type cliFlags struct {
+	quiet     bool
+	verbosity countFlag
+	vv        bool
+	format    formatFlag
+	file      string
+}
+
+// BindFlags registers the standard logging flags on fs:
+//
+//	--quiet         only log errors
+//	-v, -vv         log debug, or debug and trace, entries; more sets V's threshold
+//	--log-format    "text", "json", "logfmt", "ecs" or another format (see ParseFormat)
+//	--log-file      also write entries to this file
+//
+// The returned Option applies the parsed values, so pass it to New after
+// fs.Parse has run.
+func BindFlags(fs *flag.FlagSet) Option {
+	f := &cliFlags{}
+	fs.BoolVar(&f.quiet, "quiet", false, "only log errors")
+	fs.Var(&f.verbosity, "v", "verbose logging (repeat or use -vv for more)")
+	fs.BoolVar(&f.vv, "vv", false, "very verbose logging")
+	fs.Var(&f.format, "log-format", `log format: "text", "json", "logfmt", "ecs", ...`)
+	fs.StringVar(&f.file, "log-file", "", "also write logs to this file")
+	return f.apply
+}
+
+// BindPFlags is BindFlags for spf13/pflag flag sets.
+func BindPFlags(fs *pflag.FlagSet) Option {
+	f := &cliFlags{}
+	fs.BoolVar(&f.quiet, "quiet", false, "only log errors")
+	fs.VarPF(&f.verbosity, "verbose", "v", "verbose logging (repeat for more, e.g. -vv)").NoOptDefVal = "+1"
+	fs.Var(&f.format, "log-format", `log format: "text", "json", "logfmt", "ecs", ...`)
+	fs.StringVar(&f.file, "log-file", "", "also write logs to this file")
+	return f.apply
+}
+
+func (f *cliFlags) apply(o *options) {
+	verbosity := int(f.verbosity)
+	if f.vv {
+		verbosity = max(verbosity, 2)
+	}
+
+	switch {
+	case f.quiet:
+		WithLevel(logrus.ErrorLevel)(o)
+	case verbosity > 0:
+		WithVerbosity(verbosity)(o)
+	}
+
+	if f.format.set {
+		WithFormat(f.format.format)(o)
+	}
+	if f.file != "" {
+		WithFile(f.file, fileLoggerFormat)(o)
+	}
+}
+
+// fileLoggerFormat makes a file use the format of the logger, whatever
+// options come after the flags.
+func fileLoggerFormat(fc *fileConfig) {
+	fc.loggerFormat = true
+}
+
+// countFlag counts how many times a boolean-style flag was given.
+type countFlag int
+
+func (c *countFlag) String() string {
+	return strconv.Itoa(int(*c))
+}
+
+func (c *countFlag) Set(s string) error {
+	switch s {
+	case "true", "+1":
+		*c++
+		return nil
+	case "false":
+		*c = 0
+		return nil
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return fmt.Errorf("invalid verbosity %q", s)
+	}
+	*c = countFlag(n)
+	return nil
+}
+
+func (c *countFlag) IsBoolFlag() bool { return true }
+
+func (c *countFlag) Type() string { return "count" }
+
+type formatFlag struct {
+	format Format
+	set    bool
+}
+
+func (f *formatFlag) String() string {
+	return f.format.String()
+}
+
+func (f *formatFlag) Set(s string) error {
+	format, err := ParseFormat(s)
+	if err != nil {
+		return fmt.Errorf("unknown log format %q", s)
+	}
+	f.format, f.set = format, true
+	return nil
+}
+
+func (f *formatFlag) Type() string { return "format" }
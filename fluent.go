@@ -0,0 +1,167 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// FluentConfig describes the fluentd / Fluent Bit forward-protocol sink
+// added with WithFluent.
+type FluentConfig struct {
+	Addr string // host:port, defaults to 127.0.0.1:24224
+	Tag  string // defaults to "onylogger"
+
+	// RequireAck waits for the server to acknowledge every entry (the
+	// forward protocol's "chunk" option), resending once on a new
+	// connection if the ack does not arrive.
+	RequireAck bool
+	// TLSConfig, when set, connects over TLS (in_forward with <transport
+	// tls>); see NewTLSConfig for client certificates.
+	TLSConfig *tls.Config
+	// Queue, when set, overrides WithAsync for this sink.
+	Queue *QueueConfig
+}
+
+// WithFluent forwards every entry to fluentd or Fluent Bit using the
+// forward protocol (MessagePack over TCP).
+func WithFluent(cfg FluentConfig) Option {
+	return func(o *options) {
+		o.fluent = append(o.fluent, cfg)
+	}
+}
+
+func (l *OnyLogger) addFluent(cfg FluentConfig, o *options) {
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:24224"
+	}
+	if cfg.Tag == "" {
+		cfg.Tag = "onylogger"
+	}
+	w := &fluentWriter{cfg: cfg}
+	l.closers = append(l.closers, w)
+	l.addSink("fluentd at "+cfg.Addr, w, &fluentFormatter{tag: cfg.Tag}, o.queue(cfg.Queue))
+}
+
+// fluentFormatter encodes an entry as a forward-protocol message:
+// [tag, time, record].
+type fluentFormatter struct {
+	tag string
+}
+
+func (f *fluentFormatter) format(e *Entry, _ *bytes.Buffer) ([]byte, error) {
+	record := map[string]interface{}{
+		"level":   e.levelName(),
+		"message": stripANSIString(plainLinks(e.Message)),
+	}
+	for k, v := range e.Fields {
+		if k == "level" || k == "message" {
+			k = "fields." + k
+		}
+		// The frames are binary, so escapes are stripped here rather than
+		// by the sink (see addSink).
+		switch x := v.(type) {
+		case string:
+			v = stripANSIString(x)
+		case error:
+			v = stripANSIString(x.Error())
+		}
+		record[k] = v
+	}
+
+	b := appendMsgpackArrayHeader(nil, 3)
+	b = appendMsgpackString(b, f.tag)
+	b = appendEventTime(b, e.Time)
+	return appendMsgpack(b, record), nil
+}
+
+type fluentWriter struct {
+	cfg FluentConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (w *fluentWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	msg := p
+	var chunk string
+	if w.cfg.RequireAck {
+		// Turn [tag, time, record] into [tag, time, record, {"chunk": id}].
+		chunk = newChunkID()
+		msg = append([]byte{0x94}, p[1:]...)
+		msg = appendMsgpack(msg, map[string]interface{}{"chunk": chunk})
+	}
+
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		if err = w.send(msg, chunk); err == nil {
+			return len(p), nil
+		}
+		w.disconnect()
+	}
+	return 0, err
+}
+
+func (w *fluentWriter) send(msg []byte, chunk string) error {
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return err
+		}
+	}
+
+	w.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := w.conn.Write(msg); err != nil {
+		return err
+	}
+	if chunk == "" {
+		return nil
+	}
+
+	resp, err := readMsgpackStringMap(w.r)
+	if err != nil {
+		return err
+	}
+	if resp["ack"] != chunk {
+		return fmt.Errorf("fluent: ack %q does not match chunk %q", resp["ack"], chunk)
+	}
+	return nil
+}
+
+func (w *fluentWriter) connect() error {
+	conn, err := dial("tcp", w.cfg.Addr, 10*time.Second, w.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	w.conn, w.r = conn, bufio.NewReader(conn)
+	return nil
+}
+
+func (w *fluentWriter) disconnect() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn, w.r = nil, nil
+	}
+}
+
+func (w *fluentWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.disconnect()
+	return nil
+}
+
+func newChunkID() string {
+	var id [16]byte
+	rand.Read(id[:])
+	return base64.StdEncoding.EncodeToString(id[:])
+}
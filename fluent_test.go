@@ -0,0 +1,82 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestFluentFormat(t *testing.T) {
+	f := &fluentFormatter{tag: "app"}
+	e := &Entry{Time: time.Unix(1, 2), Level: InfoLevel, Message: "hi", Fields: Fields{"n": 1, "message": "x"}}
+	got, err := f.format(e, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "93" + // [tag, time, record]
+		"a3617070" + // "app"
+		"d7000000000100000002" + // EventTime(1s, 2ns)
+		"84" + // record, keys sorted
+		"ae6669656c64732e6d657373616765a178" + // "fields.message": "x"
+		"a56c6576656ca4696e666f" + // "level": "info"
+		"a76d657373616765a26869" + // "message": "hi"
+		"a16e01" // "n": 1
+	if hex.EncodeToString(got) != want {
+		t.Errorf("got  %x\nwant %s", got, want)
+	}
+}
+
+// TestFluentAck checks the message sent with RequireAck against the
+// forward protocol: [tag, time, record, {"chunk": id}], acknowledged with
+// {"ack": id}.
+func TestFluentAck(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+
+	e := &Entry{Time: time.Unix(1, 2), Level: InfoLevel, Message: "hi"}
+	frame, _ := (&fluentFormatter{tag: "app"}).format(e, nil)
+
+	errc := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer conn.Close()
+		r := bufio.NewReader(conn)
+		head := make([]byte, len(frame))
+		if _, err := io.ReadFull(r, head); err != nil {
+			errc <- err
+			return
+		}
+		if head[0] != 0x94 || !bytes.Equal(head[1:], frame[1:]) {
+			errc <- fmt.Errorf("frame %x, want 94%x", head, frame[1:])
+			return
+		}
+		option, err := readMsgpackStringMap(r)
+		if err != nil {
+			errc <- err
+			return
+		}
+		_, err = conn.Write(appendMsgpack(nil, map[string]interface{}{"ack": option["chunk"]}))
+		errc <- err
+	}()
+
+	w := &fluentWriter{cfg: FluentConfig{Addr: ln.Addr().String(), RequireAck: true}}
+	defer w.Close()
+	if _, err := w.Write(frame); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,299 @@
+package onylogger
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+type emojiFormatter struct {
+	levelEmojis map[logrus.Level]string
+	timestamps  *timestampCache
+	noColor     bool // plain output for files and other non-terminal sinks
+	style       Style
+	timeMode    timeMode
+	dimFields   []string // shown dimmed as key=value before the message
+	fields      FieldFilter
+	order       fieldOrder
+	links       bool                    // keep OSC 8 hyperlinks instead of spelling them out
+	columns     func() int              // wrap to this width when set and non-zero
+	bell        bool                    // ring the bell after Error and Fatal entries
+	levelLabels map[logrus.Level]string // translated level tags
+	inputLabel  string                  // translated input tag
+	location    *time.Location          // zone of timestamps, local if nil
+	localClock  *timestampCache         // set to show UTC and the local clock time
+	colorRules  []ColorRule
+	highlights  []*regexp.Regexp
+	detailed    *atomic.Bool // switches to SetDetailed's format when set
+	rawControl  bool         // leave control characters unescaped
+
+	prefixOnce sync.Once
+	prefixes   [len(levelColors)]levelPrefix
+}
+
+// levelPrefix is what the formatter writes for an entry shown the default
+// way (no custom level, emoji or color rule), built once rather than on
+// every entry.
+type levelPrefix struct {
+	color    []byte // of the timestamp
+	tag      []byte // colored, with the trailing space
+	plainTag []byte
+	emoji    []byte
+}
+
+// prefix returns the prefix of level, which must be below len(levelColors).
+func (f *emojiFormatter) prefix(level logrus.Level) *levelPrefix {
+	f.prefixOnce.Do(func() {
+		for i := range f.prefixes {
+			level := logrus.Level(i)
+			tag, translated := f.levelLabels[level]
+			if !translated {
+				tag = levelTag(level)
+			}
+			f.prefixes[i] = levelPrefix{
+				color:    []byte(levelColors[i]),
+				tag:      []byte(levelColors[i] + tag + colorReset + " "),
+				plainTag: []byte(tag + " "),
+				emoji:    []byte(f.levelEmojis[level]),
+			}
+		}
+	})
+	return &f.prefixes[level]
+}
+
+func newEmojiFormatter(precision Precision) *emojiFormatter {
+	return &emojiFormatter{
+		levelEmojis: map[logrus.Level]string{
+			logrus.InfoLevel:  "[📜] ",
+			logrus.WarnLevel:  "[⚠️ ] ",
+			logrus.ErrorLevel: "[❌] ",
+			logrus.DebugLevel: "[🐛] ",
+		},
+		timestamps: newTimestampCache(timestampLayout+precision.fraction(), precision.tick()),
+	}
+}
+
+const (
+	colorReset   = "\033[0m"
+	colorMagenta = "\033[35m"
+	colorYellow  = "\033[33m"
+	colorRed     = "\033[31m"
+	colorCyan    = "\033[36m"
+	colorGreen   = "\033[32m"
+	colorDim     = "\033[2m"
+)
+
+const timestampLayout = "2006-01-02 15:04:05"
+
+// levelColors is indexed by logrus.Level; levels without an entry use no color.
+var levelColors = [...]string{
+	logrus.PanicLevel: colorReset,
+	logrus.FatalLevel: colorReset,
+	logrus.ErrorLevel: colorRed,
+	logrus.WarnLevel:  colorYellow,
+	logrus.InfoLevel:  colorMagenta,
+	logrus.DebugLevel: colorCyan,
+	logrus.TraceLevel: colorReset,
+}
+
+func levelColor(level logrus.Level) string {
+	if int(level) < len(levelColors) {
+		return levelColors[level]
+	}
+	return colorReset
+}
+
+func (f *emojiFormatter) format(e *Entry, b *bytes.Buffer) ([]byte, error) {
+	// Write straight into the pooled buffer logrus hands us; hooks and sinks
+	// call us without one, so fall back to a fresh buffer there.
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	lineColor, ruled := f.ruleColor(e)
+	if e.custom == nil && e.logType != "input" && !ruled && int(e.Level) < len(levelColors) {
+		f.writePrefix(b, e, f.prefix(e.Level))
+	} else {
+		f.writeCustomPrefix(b, e, lineColor, ruled)
+	}
+	messageStart := b.Len()
+	if ruled {
+		f.startColor(b, lineColor)
+	}
+	switch {
+	case e.logType == "diff":
+		f.writeDiff(b, f.text(plainLinks(e.Message)))
+	case e.logType == "json":
+		f.writeJSON(b, f.text(plainLinks(e.Message)))
+	case f.links && !f.noColor:
+		if f.rawControl {
+			b.WriteString(e.Message)
+		} else {
+			b.WriteString(sanitizeLinks(e.Message))
+		}
+	default:
+		b.WriteString(f.text(plainLinks(e.Message)))
+	}
+	if ruled {
+		f.endColor(b)
+	}
+	detailed := f.isDetailed()
+	if !detailed {
+		f.writeFields(b, e)
+	}
+	if len(f.highlights) > 0 && !f.noColor {
+		highlighted := f.highlight(b.Bytes()[messageStart:])
+		b.Truncate(messageStart)
+		b.Write(highlighted)
+	}
+	if f.columns != nil {
+		if cols := f.columns(); cols > 0 {
+			wrapped := softWrap(b.Bytes(), messageStart, cols)
+			b.Reset()
+			b.Write(wrapped)
+		}
+	}
+	if detailed {
+		f.writeDetails(b, e)
+	}
+
+	// Only add a newline if "no_newline" is not set to true.
+	if !e.noNewline {
+		b.WriteByte('\n')
+		if f.ringsBell(e) {
+			b.WriteByte(bell)
+		}
+	}
+
+	return b.Bytes(), nil
+}
+
+// writePrefix writes the timestamp, tag and emoji of an entry shown the
+// default way.
+func (f *emojiFormatter) writePrefix(b *bytes.Buffer, e *Entry, p *levelPrefix) {
+	if f.timeMode != timeNone {
+		b.WriteByte('[')
+		if !f.noColor {
+			b.Write(p.color)
+		}
+		f.writeTime(b, e)
+		f.endColor(b)
+		b.WriteString("] ")
+	}
+	if len(f.dimFields) > 0 {
+		f.writeDimFields(b, e)
+	}
+	if f.style.tags() {
+		if f.noColor {
+			b.Write(p.plainTag)
+		} else {
+			b.Write(p.tag)
+		}
+	}
+	if f.style.emoji() {
+		if e.hasEmoji {
+			b.WriteString(e.emoji)
+		} else {
+			b.Write(p.emoji)
+		}
+	}
+}
+
+// writeCustomPrefix is writePrefix for entries with a custom level, input
+// entries and entries a color rule matched.
+func (f *emojiFormatter) writeCustomPrefix(b *bytes.Buffer, e *Entry, lineColor string, ruled bool) {
+	// Use custom emoji if provided; otherwise use the default for the log level.
+	emoji := e.emoji
+	if !e.hasEmoji {
+		emoji = f.levelEmojis[e.Level]
+		if e.custom != nil {
+			emoji = e.custom.emoji
+		}
+	}
+
+	colorCode := levelColor(e.Level)
+	tag, translated := f.levelLabels[e.Level]
+	if !translated {
+		tag = levelTag(e.Level)
+	}
+	if e.custom != nil {
+		colorCode, tag = e.custom.color, e.custom.tag()
+	}
+	if e.logType == "input" {
+		colorCode = colorReset // No Color for Input
+		tag = inputTag
+		if f.inputLabel != "" {
+			tag = f.inputLabel
+		}
+	}
+	if ruled {
+		colorCode = lineColor
+	}
+
+	// Apply color to the timestamp
+	if f.timeMode != timeNone {
+		b.WriteByte('[')
+		f.startColor(b, colorCode)
+		f.writeTime(b, e)
+		f.endColor(b)
+		b.WriteString("] ")
+	}
+	if len(f.dimFields) > 0 {
+		f.writeDimFields(b, e)
+	}
+	if f.style.tags() {
+		f.startColor(b, colorCode)
+		b.WriteString(tag)
+		f.endColor(b)
+		b.WriteByte(' ')
+	}
+	if f.style.emoji() {
+		b.WriteString(emoji)
+	}
+}
+
+func (f *emojiFormatter) writeTime(b *bytes.Buffer, e *Entry) {
+	if f.timeMode == timeElapsed {
+		b.Write(appendElapsed(b.AvailableBuffer(), e.Time))
+	} else {
+		b.Write(f.appendTime(b.AvailableBuffer(), e.Time))
+	}
+}
+
+func (f *emojiFormatter) writeDimFields(b *bytes.Buffer, e *Entry) {
+	var written bool
+	for _, k := range f.dimFields {
+		v, ok := e.Fields[k]
+		if !ok {
+			continue
+		}
+		if !written {
+			f.startColor(b, colorDim)
+			written = true
+		} else {
+			b.WriteByte(' ')
+		}
+		fmt.Fprintf(b, "%s=%v", k, v)
+	}
+	if written {
+		f.endColor(b)
+		b.WriteByte(' ')
+	}
+}
+
+func (f *emojiFormatter) startColor(b *bytes.Buffer, colorCode string) {
+	if !f.noColor {
+		b.WriteString(colorCode)
+	}
+}
+
+func (f *emojiFormatter) endColor(b *bytes.Buffer) {
+	if !f.noColor {
+		b.WriteString(colorReset)
+	}
+}
@@ -0,0 +1,50 @@
+package onylogger
+
+import (
+	"bytes"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func BenchmarkFormat(b *testing.B) {
+	f := newEmojiFormatter(PrecisionSeconds)
+	e := &Entry{Time: time.Now(), Level: InfoLevel, Message: "request done", Fields: Fields{"status": 200}}
+	var buf bytes.Buffer
+	b.ReportAllocs()
+	for range b.N {
+		buf.Reset()
+		f.format(e, &buf)
+	}
+}
+
+// FuzzFormat checks that no message or field makes the formatter panic or
+// write a control character or invalid UTF-8 the terminal could act on.
+func FuzzFormat(f *testing.F) {
+	for _, seed := range []string{"", "plain", "\xff\xfe bad utf-8 \xc3", "\x1b[31mred\x1b[0m", "\x1b]8;;https://example.com\x1b\\link\x1b]8;;\x1b\\", "\x9b2J", "overwrite\rthis", "\x00\x07\x08\x7f\u0085", "tab\tand\nnew line"} {
+		f.Add(seed, "key", seed)
+	}
+	f.Fuzz(func(t *testing.T, message, key, value string) {
+		e := &Entry{Time: time.Now(), Level: InfoLevel, Message: message, Fields: Fields{key: value}}
+		for _, raw := range []bool{false, true} {
+			fm := newEmojiFormatter(PrecisionSeconds)
+			fm.noColor = true
+			fm.rawControl = raw
+			var buf bytes.Buffer
+			out, err := fm.format(e, &buf)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if raw {
+				continue
+			}
+			for i := 0; i < len(out); {
+				r, size := utf8.DecodeRune(out[i:])
+				if isUnsafe(r, size) {
+					t.Fatalf("unsafe %q at %d in %q", out[i:i+size], i, out)
+				}
+				i += size
+			}
+		}
+	})
+}
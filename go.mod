@@ -2,6 +2,12 @@ module github.com/Onyz107/onylogger
 
 go 1.23.6
 
-require github.com/sirupsen/logrus v1.9.3
+require (
+	github.com/sirupsen/logrus v1.9.3
+	github.com/spf13/pflag v1.0.5
+	go.uber.org/zap v1.27.0
+	golang.org/x/sys v0.24.0
+	golang.org/x/term v0.23.0
+)
 
-require golang.org/x/sys v0.24.0 // indirect
+require go.uber.org/multierr v1.10.0 // indirect
@@ -0,0 +1,49 @@
+package onylogger
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Heartbeat logs message at Info level every interval, with the process
+// uptime and the fields returned by stats, until ctx is done. It returns
+// right away; the heartbeat runs in its own goroutine. An interval that is
+// not positive is reported (see SetErrorHandler) and starts nothing.
+//
+//	log.Heartbeat(ctx, time.Minute, "Still alive", func() onylogger.Fields {
+//		return onylogger.Fields{"queue": q.Len()}
+//	})
+func (l *OnyLogger) Heartbeat(ctx context.Context, interval time.Duration, message string, stats ...func() Fields) {
+	if interval <= 0 {
+		l.reportError(fmt.Errorf("invalid heartbeat interval %s", interval))
+		return
+	}
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			if !l.enabled(InfoLevel) {
+				continue
+			}
+
+			fields := logrus.Fields{}
+			for _, fn := range stats {
+				for k, v := range fn() {
+					fields[k] = v
+				}
+			}
+			fields["emoji"] = "[💓] "
+			fields["uptime"] = time.Since(processStart).Round(time.Second).String()
+			l.emit(InfoLevel, fields, message)
+		}
+	}()
+}
@@ -0,0 +1,76 @@
+package onylogger
+
+import (
+	"bytes"
+	"regexp"
+)
+
+const (
+	colorHighlight    = "\033[43m" // yellow background
+	colorHighlightEnd = "\033[49m" // default background, keeping the foreground
+)
+
+// WithHighlight marks the matches of pattern on console lines with a
+// yellow background, like grep --color over a live log stream. Matches are
+// looked for in the message and fields, between any color codes.
+//
+//	onylogger.WithHighlight(regexp.MustCompile(`(?i)timeout|refused`))
+func WithHighlight(pattern *regexp.Regexp) Option {
+	return func(o *options) {
+		o.highlight = append(o.highlight, pattern)
+	}
+}
+
+// highlight returns b with the matches of f's patterns marked, leaving
+// escape sequences alone.
+func (f *emojiFormatter) highlight(b []byte) []byte {
+	out := make([]byte, 0, len(b)+32)
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\033')
+		if i < 0 {
+			i = len(b)
+		}
+		out = f.highlightText(out, b[:i])
+		b = b[i:]
+		if len(b) > 0 {
+			n := escapeLen(string(b[:min(len(b), 256)]))
+			out = append(out, b[:n]...)
+			b = b[n:]
+		}
+	}
+	return out
+}
+
+func (f *emojiFormatter) highlightText(out, text []byte) []byte {
+	var matches [][]int
+	for _, re := range f.highlights {
+		matches = append(matches, re.FindAllIndex(text, -1)...)
+	}
+	if len(matches) == 0 {
+		return append(out, text...)
+	}
+
+	// Mark every byte covered by a match, so overlapping matches from
+	// several patterns merge into one span.
+	marked := make([]bool, len(text))
+	for _, m := range matches {
+		for i := m[0]; i < m[1]; i++ {
+			marked[i] = true
+		}
+	}
+	for i := 0; i < len(text); {
+		j := i
+		for j < len(text) && marked[j] == marked[i] {
+			j++
+		}
+		if marked[i] {
+			out = append(out, colorHighlight...)
+			out = append(out, text[i:j]...)
+			out = append(out, colorHighlightEnd...)
+		} else {
+			out = append(out, text[i:j]...)
+		}
+		i = j
+	}
+	return out
+}
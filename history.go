@@ -0,0 +1,129 @@
+package onylogger
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"os"
+	"sync"
+)
+
+// History holds earlier answers to a prompt, recalled with the up and down
+// arrow keys in InputHistory. Create one per prompt and keep it around.
+type History struct {
+	mu      sync.Mutex
+	entries []string
+	max     int
+	path    string
+}
+
+// NewHistory returns an in-memory history keeping the last max answers
+// (all of them if max is 0).
+func NewHistory(max int) *History {
+	return &History{max: max}
+}
+
+// LoadHistory returns a history persisted to the file at path, one answer
+// per line, reading the answers already in it. A missing file is created
+// when the first answer is added.
+func LoadHistory(path string, max int) (*History, error) {
+	h := &History{max: max, path: path}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		h.entries = append(h.entries, scanner.Text())
+	}
+	h.trim()
+	return h, scanner.Err()
+}
+
+// Add appends answer, unless it is empty or repeats the latest one.
+func (h *History) Add(answer string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if answer == "" || len(h.entries) > 0 && h.entries[len(h.entries)-1] == answer {
+		return nil
+	}
+	h.entries = append(h.entries, answer)
+	trimmed := h.trim()
+	if h.path == "" {
+		return nil
+	}
+	if trimmed {
+		return h.rewrite()
+	}
+	f, err := os.OpenFile(h.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(answer + "\n"); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// Entries returns the answers, oldest first.
+func (h *History) Entries() []string {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]string(nil), h.entries...)
+}
+
+// trim drops the oldest entries beyond max and reports whether it did.
+func (h *History) trim() bool {
+	if h.max <= 0 || len(h.entries) <= h.max {
+		return false
+	}
+	h.entries = append([]string(nil), h.entries[len(h.entries)-h.max:]...)
+	return true
+}
+
+// rewrite replaces the history file with the current entries.
+func (h *History) rewrite() error {
+	tmp := h.path + ".tmp"
+	f, err := os.OpenFile(tmp, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o600)
+	if err != nil {
+		return err
+	}
+	w := bufio.NewWriter(f)
+	for _, e := range h.entries {
+		w.WriteString(e + "\n")
+	}
+	if err := w.Flush(); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return os.Rename(tmp, h.path)
+}
+
+// InputHistory prompts like Input, letting the user recall earlier answers
+// from h with the up and down arrow keys. The answer is added to h.
+func (l *OnyLogger) InputHistory(message string, h *History) (string, error) {
+	e := &lineEditor{history: h.Entries(), redraw: func() { l.prompt(message) }}
+	if err := l.prompt(message); err != nil {
+		return "", err
+	}
+	answer, err := e.readLine(context.Background())
+	if err != nil {
+		return "", err
+	}
+	if err := h.Add(answer); err != nil {
+		l.emitf(ErrorLevel, nil, "Failed to save input history: %v", err)
+	}
+	return answer, nil
+}
@@ -0,0 +1,37 @@
+package onylogger
+
+import (
+	"fmt"
+	"math"
+	"time"
+)
+
+// Bytes tags a field value as a size in bytes. Text outputs show it
+// humanized ("3.4 MiB"); JSON outputs keep the plain number:
+//
+//	log.WithField("size", onylogger.Bytes(n)).Info("Uploaded")
+type Bytes int64
+
+func (b Bytes) String() string {
+	const unit = 1024
+	if b > -unit && b < unit {
+		return fmt.Sprintf("%d B", int64(b))
+	}
+	n, exp := float64(b)/unit, 0
+	for math.Abs(n) >= unit && exp < 5 {
+		n /= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %ciB", n, "KMGTPE"[exp])
+}
+
+// humanizeDuration rounds d to three significant digits, so 1.234567s
+// becomes 1.23s.
+func humanizeDuration(d time.Duration) string {
+	abs := d.Abs()
+	if abs < 1000 {
+		return d.String()
+	}
+	precision := time.Duration(math.Pow10(int(math.Log10(float64(abs))) - 2))
+	return d.Round(precision).String()
+}
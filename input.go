@@ -0,0 +1,78 @@
+package onylogger
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// Input logs the provided message with the "📝" emoji without a newline,
+// then reads user input and assigns it to the provided pointer.
+func (l *OnyLogger) Input(message string, userInput *string) {
+	l.mustPrompt(message)
+	*userInput, _ = readLine()
+}
+
+// InputDefault prompts with the default shown in brackets, "Port [8080]:",
+// and returns def if the answer is empty.
+func (l *OnyLogger) InputDefault(message, def string) string {
+	l.mustPrompt(withDefault(message, def))
+	answer, _ := readLine()
+	if strings.TrimSpace(answer) == "" {
+		return def
+	}
+	return answer
+}
+
+// withDefault puts "[def]" in front of the message's trailing colon.
+func withDefault(message, def string) string {
+	if def == "" {
+		return message
+	}
+	return strings.TrimSuffix(message, ":") + " [" + def + "]:"
+}
+
+// InputContext is Input that gives up when ctx is done, returning ctx.Err().
+// An answer typed after that is returned by the next prompt.
+func (l *OnyLogger) InputContext(ctx context.Context, message string) (string, error) {
+	if err := l.prompt(message); err != nil {
+		return "", err
+	}
+	answer, err := readLineContext(ctx)
+	if ctx.Err() != nil {
+		fmt.Println()
+	}
+	return answer, err
+}
+
+// prompt logs message as an input prompt and leaves the cursor behind it.
+// When stdin is not a terminal it follows the NonInteractivePolicy.
+func (l *OnyLogger) prompt(message string) error {
+	if !stdinIsTerminal() {
+		if l.opts.nonInteractive == NonInteractiveFail {
+			return ErrNonInteractive
+		}
+		l.Flush()
+		l.render.Write([]byte(message + " "))
+		return nil
+	}
+
+	// Mark the entry as a prompt: input styling, the "📝" emoji, and no
+	// newline so the answer is typed on the same line.
+	l.emit(InfoLevel, Fields{
+		"log_type":   "input",
+		"emoji":      "[📝] ",
+		"no_newline": true,
+	}, message)
+	l.Flush()
+	fmt.Print(" ")
+	return nil
+}
+
+// mustPrompt is prompt for the prompts that cannot return an error.
+func (l *OnyLogger) mustPrompt(message string) {
+	if err := l.prompt(message); err != nil {
+		l.emitf(FatalLevel, nil, "Cannot ask %q: %v", message, err)
+		l.backend.exit(1)
+	}
+}
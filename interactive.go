@@ -0,0 +1,35 @@
+package onylogger
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/term"
+)
+
+// NonInteractivePolicy decides what prompts do when stdin is not a
+// terminal, e.g. in CI or with input piped in.
+type NonInteractivePolicy int
+
+const (
+	// NonInteractiveRead reads answers from stdin line by line and prints
+	// prompts as plain text, without colors or escape codes. The default.
+	NonInteractiveRead NonInteractivePolicy = iota
+	// NonInteractiveFail makes prompts fail with ErrNonInteractive; Input
+	// and InputDefault, which cannot return errors, log it at Fatal level.
+	NonInteractiveFail
+)
+
+// ErrNonInteractive is returned by prompts under NonInteractiveFail.
+var ErrNonInteractive = errors.New("onylogger: cannot prompt, stdin is not a terminal")
+
+// WithNonInteractive sets what prompts do when stdin is not a terminal.
+func WithNonInteractive(policy NonInteractivePolicy) Option {
+	return func(o *options) {
+		o.nonInteractive = policy
+	}
+}
+
+func stdinIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
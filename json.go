@@ -0,0 +1,151 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+// Format selects the encoding of an output.
+type Format int
+
+const (
+	// FormatText is the human-readable emoji format.
+	FormatText Format = iota
+	// FormatJSON writes one JSON object per entry.
+	FormatJSON
+	// FormatDocker writes the text format in records of Docker's json-file
+	// log driver, for tools that already read those.
+	FormatDocker
+	// FormatLogstash writes JSON in the classic Logstash event schema:
+	// @timestamp, @version, message and level, with fields at the top level.
+	FormatLogstash
+	// FormatLogfmt writes one line of key=value pairs per entry.
+	FormatLogfmt
+	// FormatECS writes JSON in the Elastic Common Schema: @timestamp,
+	// log.level, message and ecs.version, with fields at the top level.
+	FormatECS
+)
+
+// formatEnv names the environment variable that selects the console
+// format by name (see ParseFormat) when WithFormat is not given.
+const formatEnv = "ONYLOGGER_FORMAT"
+
+// WithFormat sets the console encoding, overriding ONYLOGGER_FORMAT.
+func WithFormat(format Format) Option {
+	return func(o *options) {
+		o.format, o.formatSet = format, true
+	}
+}
+
+// FileFormat sets the encoding of a file sink.
+func FileFormat(format Format) FileOption {
+	return func(fc *fileConfig) {
+		fc.format = format
+	}
+}
+
+// isInternalField reports whether key is one of the control fields the
+// package uses to steer formatting; those are never written out as data.
+func isInternalField(key string) bool {
+	switch key {
+	case "emoji", "log_type", "no_newline", "custom_level":
+		return true
+	}
+	return false
+}
+
+// ecsVersion is the version of the Elastic Common Schema FormatECS follows.
+const ecsVersion = "8.11.0"
+
+type jsonFormatter struct {
+	timestamps *timestampCache
+	fields     FieldFilter
+	order      fieldOrder
+	logstash   bool           // use the Logstash event schema
+	ecs        bool           // use the Elastic Common Schema
+	location   *time.Location // zone of timestamps, local if nil
+}
+
+func newJSONFormatter(precision Precision) *jsonFormatter {
+	return &jsonFormatter{
+		timestamps: newTimestampCache(time.RFC3339[:19]+precision.fraction()+time.RFC3339[19:], precision.tick()),
+	}
+}
+
+func (f *jsonFormatter) format(e *Entry, b *bytes.Buffer) ([]byte, error) {
+	timeKey, levelKey, messageKey, versionKey := "time", "level", "msg", ""
+	switch {
+	case f.logstash:
+		timeKey, messageKey, versionKey = "@timestamp", "message", "@version"
+	case f.ecs:
+		timeKey, levelKey, messageKey, versionKey = "@timestamp", "log.level", "message", "ecs.version"
+	}
+
+	data := make(Fields, len(e.Fields)+4)
+	for k, v := range e.Fields {
+		if !f.fields.keep(k) {
+			continue
+		}
+		switch k {
+		case timeKey, levelKey, messageKey, versionKey:
+			// Keep user fields from clobbering the entry's own keys.
+			k = "fields." + k
+		}
+		data[k] = jsonFieldValue(v)
+	}
+	t := e.Time
+	if f.location != nil {
+		t = t.In(f.location)
+	}
+	data[timeKey] = string(f.timestamps.appendTo(nil, t))
+	data[levelKey] = e.levelName()
+	data[messageKey] = plainLinks(e.Message)
+	switch {
+	case f.logstash:
+		data[versionKey] = "1"
+	case f.ecs:
+		data[versionKey] = ecsVersion
+	}
+
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+
+	if f.order != nil {
+		if err := encodeOrdered(b, data, f.order); err != nil {
+			return nil, err
+		}
+		return b.Bytes(), nil
+	}
+
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// jsonFieldValue replaces values that would marshal as reflection noise:
+// errors (most marshal as {}) and fmt.Stringers without their own JSON or
+// text encoding. Numbers, like Bytes and time.Duration, stay numbers.
+func jsonFieldValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []byte:
+		return bytesString(v)
+	case error:
+		return fmt.Sprint(v)
+	case json.Marshaler, encoding.TextMarshaler:
+		return v
+	case fmt.Stringer:
+		switch reflect.ValueOf(v).Kind() {
+		case reflect.Struct, reflect.Pointer, reflect.Interface, reflect.Map, reflect.Slice, reflect.Array:
+			return fmt.Sprint(v)
+		}
+	}
+	return v
+}
@@ -0,0 +1,88 @@
+package onylogger
+
+import (
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// KlogWriter returns a writer that takes klog's output, as used by
+// Kubernetes client-go, and logs each message through l at its klog
+// severity, keeping klog's timestamp and the source location in a "source"
+// field. Structured klog messages (InfoS, ErrorS) have their key/value
+// pairs turned into fields.
+//
+//	fs := flag.NewFlagSet("klog", flag.ExitOnError)
+//	klog.InitFlags(fs)
+//	fs.Set("logtostderr", "false")
+//	fs.Set("one_output", "true")
+//	klog.SetOutput(log.KlogWriter())
+//
+// Without one_output klog writes every message once for each severity at or
+// below its own, and the writer sees warnings twice and errors three times.
+func (l *OnyLogger) KlogWriter() io.Writer {
+	return klogWriter{l: l}
+}
+
+type klogWriter struct {
+	l *OnyLogger
+}
+
+// klogHeader matches klog's "Lmmdd hh:mm:ss.uuuuuu threadid file:line] ".
+var klogHeader = regexp.MustCompile(`^([IWEF])(\d{4} \d{2}:\d{2}:\d{2}\.\d{6})\s+\d+ ([^\]]+)\] `)
+
+var klogLevels = map[byte]logrus.Level{
+	'I': logrus.InfoLevel,
+	'W': logrus.WarnLevel,
+	'E': logrus.ErrorLevel,
+	'F': logrus.FatalLevel, // klog exits by itself after writing it
+}
+
+func (w klogWriter) Write(p []byte) (int, error) {
+	line := strings.TrimSuffix(string(p), "\n")
+	m := klogHeader.FindStringSubmatchIndex(line)
+	if m == nil {
+		w.l.emit(InfoLevel, nil, line)
+		return len(p), nil
+	}
+
+	level := klogLevels[line[m[2]]]
+	fields := logrus.Fields{"source": line[m[6]:m[7]]}
+	message := parseKlogMessage(line[m[1]:], fields)
+
+	t, err := time.ParseInLocation("0102 15:04:05.000000", line[m[4]:m[5]], time.Local)
+	if err == nil {
+		now := time.Now()
+		t = t.AddDate(now.Year(), 0, 0)
+		if t.After(now.Add(24 * time.Hour)) {
+			t = t.AddDate(-1, 0, 0) // logged last December
+		}
+	} else {
+		t = time.Time{} // now
+	}
+	w.l.backend.log(level, t, fields, message)
+	return len(p), nil
+}
+
+// parseKlogMessage splits a structured klog message, `"msg" key="value"`,
+// into its message and fields. Other messages are returned as they are.
+func parseKlogMessage(s string, fields logrus.Fields) string {
+	if !strings.HasPrefix(s, `"`) {
+		return s
+	}
+	quoted, err := strconv.QuotedPrefix(s)
+	if err != nil {
+		return s
+	}
+	message, _ := strconv.Unquote(quoted)
+	if rest := s[len(quoted):]; rest != "" {
+		if left := splitTextFields(rest, fields); strings.TrimSpace(left) != "" {
+			return s // not all key/value pairs, keep the message whole
+		}
+	}
+	return message
+}
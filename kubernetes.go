@@ -0,0 +1,53 @@
+package onylogger
+
+import (
+	"os"
+	"strings"
+)
+
+// kubernetesNamespaceFile holds the pod's namespace in every container
+// that mounts a service account token.
+const kubernetesNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// WithKubernetesInfo adds k8s_namespace, k8s_pod, k8s_node and
+// k8s_container fields to every entry when running in a Kubernetes
+// cluster, for telling apart the logs of replicas. They are read from the
+// environment variables the downward API is usually mapped to (POD_NAME,
+// POD_NAMESPACE, NODE_NAME and CONTAINER_NAME, or the same with a K8S_
+// prefix), falling back to the service account's namespace and to the
+// hostname, which is the pod name. Outside a cluster it does nothing. Like
+// the WithServiceInfo fields, they show up in JSON outputs only.
+//
+//	env:
+//	  - name: NODE_NAME
+//	    valueFrom: {fieldRef: {fieldPath: spec.nodeName}}
+func WithKubernetesInfo() Option {
+	return func(o *options) {
+		o.kubernetes = true
+	}
+}
+
+// kubernetesFields returns the metadata of the pod, or nil outside a
+// cluster.
+func kubernetesFields() Fields {
+	if os.Getenv("KUBERNETES_SERVICE_HOST") == "" {
+		return nil
+	}
+
+	fields := make(Fields)
+	set := func(key string, values ...string) {
+		for _, v := range values {
+			if v = strings.TrimSpace(v); v != "" {
+				fields[key] = v
+				return
+			}
+		}
+	}
+	namespace, _ := os.ReadFile(kubernetesNamespaceFile)
+	hostname, _ := os.Hostname()
+	set("k8s_namespace", os.Getenv("POD_NAMESPACE"), os.Getenv("K8S_POD_NAMESPACE"), string(namespace))
+	set("k8s_pod", os.Getenv("POD_NAME"), os.Getenv("K8S_POD_NAME"), hostname)
+	set("k8s_node", os.Getenv("NODE_NAME"), os.Getenv("K8S_NODE_NAME"))
+	set("k8s_container", os.Getenv("CONTAINER_NAME"), os.Getenv("K8S_CONTAINER_NAME"))
+	return fields
+}
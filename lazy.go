@@ -0,0 +1,47 @@
+package onylogger
+
+import (
+	"fmt"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Lazy defers computing a field value until an entry is actually written:
+//
+//	log.WithField("state", onylogger.Lazy(dumpState)).Debug("tick")
+//
+// When Debug is disabled the function is never called.
+type Lazy func() interface{}
+
+// lazyHook resolves Lazy field values before any formatter or sink sees the
+// entry. Hooks only fire for enabled levels, which is what makes it lazy.
+// A function that panics is reported and its value replaced by a note.
+type lazyHook struct {
+	report func(error)
+}
+
+func (lazyHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h lazyHook) Fire(entry *logrus.Entry) error {
+	if entry.Level == dropLevel {
+		return nil
+	}
+	for k, v := range entry.Data {
+		if fn, ok := v.(Lazy); ok {
+			entry.Data[k] = h.resolve(k, fn)
+		}
+	}
+	return nil
+}
+
+func (h lazyHook) resolve(key string, fn Lazy) (v interface{}) {
+	defer func() {
+		if r := recover(); r != nil {
+			h.report(fmt.Errorf("lazy field %s panicked: %v", key, r))
+			v = fmt.Sprintf("<PANIC=%v>", r)
+		}
+	}()
+	return fn()
+}
@@ -0,0 +1,27 @@
+package onylogger
+
+import (
+	"io"
+	"testing"
+)
+
+func BenchmarkLazyDisabled(b *testing.B) {
+	l := New(WithLevel(InfoLevel))
+	l.SetOutput(io.Discard)
+	fields := Fields{"state": Lazy(func() interface{} {
+		b.Fatal("Lazy value computed for a disabled level")
+		return nil
+	})}
+	debug := func() {
+		l.LogT(DebugLevel, "tick {state}", fields)
+		l.Debugfe("[⏱️] ", "tick %d", 1)
+	}
+	if allocs := testing.AllocsPerRun(100, debug); allocs != 0 {
+		b.Fatalf("disabled Debug calls made %v allocations, want 0", allocs)
+	}
+
+	b.ReportAllocs()
+	for range b.N {
+		debug()
+	}
+}
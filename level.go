@@ -0,0 +1,64 @@
+package onylogger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// CustomLevel is a level added with NewLevel. It is filtered, routed and
+// counted as its severity, one of logrus's levels, and shown with its own
+// name, emoji and color. Outputs built from Entry snapshots, like Query,
+// CSV and SQLite, see the severity.
+type CustomLevel struct {
+	name     string
+	severity logrus.Level
+	emoji    string
+	color    string
+}
+
+// NewLevel defines a level. Severity places it among the others: a Notice
+// level with severity Info shows whenever Info does, and a Security level
+// with severity Warn reaches every sink that takes warnings. Color is the
+// ANSI escape for its timestamp and tag, "" for none.
+//
+//	var Notice = onylogger.NewLevel("notice", onylogger.InfoLevel, "[🔔] ", "\033[34m")
+//
+//	log.Logc(Notice, "Maintenance window starts at 02:00")
+func NewLevel(name string, severity Level, emoji, color string) *CustomLevel {
+	if color == "" {
+		color = colorReset
+	}
+	return &CustomLevel{name: name, severity: severity, emoji: emoji, color: color}
+}
+
+// Name returns the level's name, as written in JSON output.
+func (c *CustomLevel) Name() string { return c.name }
+
+// Severity returns the logrus level c is handled as.
+func (c *CustomLevel) Severity() Level { return c.severity }
+
+func (c *CustomLevel) tag() string {
+	return "[" + strings.ToUpper(c.name) + "]"
+}
+
+// Logc logs at a custom level.
+func (l *OnyLogger) Logc(level *CustomLevel, args ...interface{}) {
+	if l.enabled(level.severity) {
+		l.emit(level.severity, Fields{"custom_level": level}, fmt.Sprint(args...))
+	}
+}
+
+// Logcf formats and logs at a custom level.
+func (l *OnyLogger) Logcf(level *CustomLevel, format string, args ...interface{}) {
+	l.emitf(level.severity, Fields{"custom_level": level}, format, args...)
+}
+
+// levelName is the level as outputs write it.
+func (e *Entry) levelName() string {
+	if e.custom != nil {
+		return e.custom.name
+	}
+	return e.Level.String()
+}
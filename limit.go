@@ -0,0 +1,57 @@
+package onylogger
+
+import "unicode/utf8"
+
+// WithSizeLimits caps the size of messages and of string field values, in
+// bytes, cutting what is over with a marker such as "…(truncated 14.0 KiB)",
+// so that logging a huge blob by accident does not flood the terminal or
+// the sinks. Limits apply to every output; 0 leaves that kind unlimited.
+//
+//	onylogger.WithSizeLimits(8<<10, 2<<10)
+func WithSizeLimits(message, field int) Option {
+	return func(o *options) {
+		o.maxMessage, o.maxField = message, field
+	}
+}
+
+// sizeLimits truncates oversized messages and fields before any formatter
+// or sink sees the entry.
+type sizeLimits struct {
+	message, field int
+}
+
+func (l sizeLimits) process(e Entry) (Entry, bool) {
+	if l.message > 0 {
+		e.Message = truncateWithMarker(e.Message, l.message)
+	}
+	if l.field <= 0 {
+		return e, true
+	}
+	for k, v := range e.Fields {
+		switch v := v.(type) {
+		case string:
+			if len(v) > l.field {
+				e.Fields[k] = truncateWithMarker(v, l.field)
+			}
+		case []byte:
+			// Binary values are already shown as a preview of bounded size.
+			if s := bytesString(v); len(v) > l.field && s == string(v) {
+				e.Fields[k] = truncateWithMarker(s, l.field)
+			}
+		}
+	}
+	return e, true
+}
+
+// truncateWithMarker cuts s to at most max bytes, on a character boundary, and notes
+// how much was cut.
+func truncateWithMarker(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	cut := max
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + "…(truncated " + Bytes(len(s)-cut).String() + ")"
+}
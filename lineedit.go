@@ -0,0 +1,232 @@
+package onylogger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// InputComplete prompts like Input, with Tab completion: complete returns
+// the candidates for what was typed so far. One Tab completes as far as the
+// candidates agree, a second one lists them. When stdin is not a terminal
+// the answer is read as a plain line.
+//
+//	ctx, err := log.InputComplete("Kube context:", func(prefix string) []string {
+//		return matching(contexts, prefix)
+//	})
+func (l *OnyLogger) InputComplete(message string, complete func(prefix string) []string) (string, error) {
+	e := &lineEditor{complete: complete, redraw: func() { l.prompt(message) }}
+	if err := l.prompt(message); err != nil {
+		return "", err
+	}
+	return e.readLine(context.Background())
+}
+
+// lineEditor reads a line in raw terminal mode, echoing it itself so that
+// keys like Tab can do more than insert characters.
+type lineEditor struct {
+	complete func(prefix string) []string
+	redraw   func() // prints the prompt again, after listing candidates
+	history  []string
+
+	line    []rune
+	lastTab bool
+	recall  int    // position in history while browsing it
+	draft   string // the line typed before browsing history
+}
+
+// Keys that are not characters; escape sequences map to values above the
+// Unicode range.
+const (
+	keyTab    = 9
+	keyCtrlU  = 21
+	keyEscape = 27
+)
+
+const (
+	keyUnknown = utf8.MaxRune + 1 + iota
+	keyUp
+	keyDown
+	keyPageUp
+	keyPageDown
+	keyHome
+	keyEnd
+)
+
+func (e *lineEditor) readLine(ctx context.Context) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return readLineContext(ctx)
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+
+	e.recall = len(e.history)
+	for {
+		r, err := readKey(ctx)
+		if err != nil {
+			fmt.Print("\r\n")
+			return "", err
+		}
+		tab := r == keyTab
+		switch r {
+		case '\r', '\n':
+			fmt.Print("\r\n")
+			return string(e.line), nil
+		case keyCtrlC:
+			fmt.Print("\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(e.line) == 0 {
+				fmt.Print("\r\n")
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if len(e.line) > 0 {
+				w := runeWidth(e.line[len(e.line)-1])
+				e.line = e.line[:len(e.line)-1]
+				fmt.Print(strings.Repeat("\b", w) + strings.Repeat(" ", w) + strings.Repeat("\b", w))
+			}
+		case keyCtrlU:
+			e.setLine("")
+		case keyTab:
+			if e.complete != nil {
+				e.tab()
+			}
+		case keyUp:
+			if e.recall > 0 {
+				if e.recall == len(e.history) {
+					e.draft = string(e.line)
+				}
+				e.recall--
+				e.setLine(e.history[e.recall])
+			}
+		case keyDown:
+			if e.recall < len(e.history) {
+				e.recall++
+				if e.recall == len(e.history) {
+					e.setLine(e.draft)
+				} else {
+					e.setLine(e.history[e.recall])
+				}
+			}
+		default:
+			if r >= ' ' && r < keyUnknown {
+				e.line = append(e.line, r)
+				fmt.Print(string(r))
+			}
+		}
+		e.lastTab = tab
+	}
+}
+
+// setLine replaces what is shown and typed with s.
+func (e *lineEditor) setLine(s string) {
+	// Back up by columns, not runes: CJK characters and emoji take two.
+	fmt.Print(strings.Repeat("\b", visibleWidth([]byte(string(e.line)))) + "\033[K" + s)
+	e.line = []rune(s)
+}
+
+func (e *lineEditor) tab() {
+	prefix := string(e.line)
+	candidates := e.complete(prefix)
+	if len(candidates) == 0 {
+		return
+	}
+	if common := commonPrefix(candidates); len(common) > len(prefix) && strings.HasPrefix(common, prefix) {
+		e.setLine(common)
+		return
+	}
+	if !e.lastTab {
+		return
+	}
+
+	sorted := append([]string(nil), candidates...)
+	sort.Strings(sorted)
+	fmt.Print("\r\n" + strings.Join(sorted, "  ") + "\r\n")
+	e.redraw()
+	fmt.Print(string(e.line))
+}
+
+func commonPrefix(words []string) string {
+	prefix := words[0]
+	for _, w := range words[1:] {
+		for !strings.HasPrefix(w, prefix) {
+			_, size := utf8.DecodeLastRuneInString(prefix)
+			prefix = prefix[:len(prefix)-size]
+		}
+	}
+	return prefix
+}
+
+// escapeTimeout is how long readKey waits for the rest of an escape
+// sequence before taking Escape as a key press of its own. Terminals send
+// a sequence in one write, so it only matters when Escape was pressed.
+const escapeTimeout = 50 * time.Millisecond
+
+// readKey reads a character or a key sent as an escape sequence.
+func readKey(ctx context.Context) (rune, error) {
+	r, err := readRune(ctx)
+	if err != nil || r != keyEscape {
+		return r, err
+	}
+	seqCtx, cancel := context.WithTimeout(ctx, escapeTimeout)
+	c, err := readByteContext(seqCtx)
+	cancel()
+	if err != nil {
+		if ctx.Err() == nil {
+			return keyEscape, nil // nothing followed
+		}
+		return 0, err
+	}
+	if c != '[' && c != 'O' {
+		// Escape, then another key.
+		unreadByte(c)
+		return keyEscape, nil
+	}
+	// CSI and SS3 sequences end with a byte in 0x40-0x7e, e.g. "\033[A" or
+	// "\033[5~".
+	var params []byte
+	for {
+		c, err = readByteContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if c >= 0x40 && c <= 0x7e {
+			break
+		}
+		params = append(params, c)
+	}
+	switch c {
+	case 'A':
+		return keyUp, nil
+	case 'B':
+		return keyDown, nil
+	case 'H':
+		return keyHome, nil
+	case 'F':
+		return keyEnd, nil
+	case '~':
+		switch string(params) {
+		case "5":
+			return keyPageUp, nil
+		case "6":
+			return keyPageDown, nil
+		case "1", "7":
+			return keyHome, nil
+		case "4", "8":
+			return keyEnd, nil
+		}
+	}
+	return keyUnknown, nil
+}
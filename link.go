@@ -0,0 +1,72 @@
+package onylogger
+
+import (
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	osc8Start = "\033]8;;"
+	osc8End   = "\033\\"
+)
+
+// Link returns text as a hyperlink to url, for use in messages:
+//
+//	log.Errorf("Checkout is failing, see %s", log.Link("the dashboard", url))
+//
+// Terminals that support OSC 8 hyperlinks show a clickable text; all other
+// outputs, including files and JSON, get "text (url)".
+func (l *OnyLogger) Link(text, url string) string {
+	return osc8Start + url + osc8End + text + osc8Start + osc8End
+}
+
+// plainLinks replaces the hyperlinks made by Link with "text (url)".
+func plainLinks(s string) string {
+	if !strings.Contains(s, osc8Start) {
+		return s
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, osc8Start)
+		if start < 0 {
+			break
+		}
+		rest := s[start+len(osc8Start):]
+		url, rest, ok1 := strings.Cut(rest, osc8End)
+		text, rest, ok2 := strings.Cut(rest, osc8Start+osc8End)
+		if !ok1 || !ok2 {
+			break
+		}
+		b.WriteString(s[:start])
+		if text == "" || text == url {
+			b.WriteString(url)
+		} else {
+			b.WriteString(text + " (" + url + ")")
+		}
+		s = rest
+	}
+	b.WriteString(s)
+	return b.String()
+}
+
+// supportsHyperlinks guesses whether the terminal renders OSC 8 links.
+// FORCE_HYPERLINK=1 or 0 overrides the guess.
+func supportsHyperlinks() bool {
+	if force := os.Getenv("FORCE_HYPERLINK"); force != "" {
+		return force != "0"
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("WT_SESSION") != "" || os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("DOMTERM") != "" {
+		return true
+	}
+	if v, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && v >= 5000 {
+		return true // GNOME Terminal, Tilix and other VTE terminals
+	}
+	term := os.Getenv("TERM")
+	return strings.Contains(term, "kitty") || strings.Contains(term, "alacritty") || strings.HasPrefix(term, "foot")
+}
@@ -0,0 +1,81 @@
+package onylogger
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Locale adapts text output to the language of a CLI's users. JSON output
+// keeps the canonical level names and timestamps, since it is read by
+// machines.
+type Locale struct {
+	// TimeLayout is the time.Format layout of text timestamps, e.g.
+	// "02.01.2006 15:04:05". Sub-second precision is added after the
+	// seconds. Text files with another layout than the default cannot be
+	// read back with ParseFile.
+	TimeLayout string
+	// Levels translates the level tags shown with StyleTags, e.g.
+	// onylogger.WarnLevel: "WARNUNG". Levels not in the map keep their name.
+	Levels map[Level]string
+	// Input translates the tag of prompts.
+	Input string
+}
+
+var (
+	// LocaleGerman uses German level tags and day-first dates.
+	LocaleGerman = Locale{
+		TimeLayout: "02.01.2006 15:04:05",
+		Levels: map[logrus.Level]string{
+			logrus.PanicLevel: "PANIK",
+			logrus.FatalLevel: "FATAL",
+			logrus.ErrorLevel: "FEHLER",
+			logrus.WarnLevel:  "WARNUNG",
+			logrus.InfoLevel:  "INFO",
+			logrus.DebugLevel: "DEBUG",
+			logrus.TraceLevel: "TRACE",
+		},
+		Input: "EINGABE",
+	}
+	// LocaleFrench uses French level tags and day-first dates.
+	LocaleFrench = Locale{
+		TimeLayout: "02/01/2006 15:04:05",
+		Levels: map[logrus.Level]string{
+			logrus.PanicLevel: "PANIQUE",
+			logrus.FatalLevel: "FATAL",
+			logrus.ErrorLevel: "ERREUR",
+			logrus.WarnLevel:  "AVERTISSEMENT",
+			logrus.InfoLevel:  "INFO",
+			logrus.DebugLevel: "DÉBOGAGE",
+			logrus.TraceLevel: "TRACE",
+		},
+		Input: "SAISIE",
+	}
+)
+
+// WithLocale sets the timestamp layout and level tags of text output.
+func WithLocale(locale Locale) Option {
+	return func(o *options) {
+		o.locale = &locale
+	}
+}
+
+// applyLocale sets up f for the configured locale, if any.
+func (o *options) applyLocale(f *emojiFormatter) {
+	if o.locale == nil {
+		return
+	}
+	if layout := o.locale.TimeLayout; layout != "" {
+		if fraction := o.precision.fraction(); fraction != "" {
+			layout = strings.Replace(layout, "05", "05"+fraction, 1)
+		}
+		f.timestamps = newTimestampCache(layout, o.precision.tick())
+	}
+	f.levelLabels = make(map[logrus.Level]string, len(o.locale.Levels))
+	for level, label := range o.locale.Levels {
+		f.levelLabels[level] = "[" + label + "]"
+	}
+	if o.locale.Input != "" {
+		f.inputLabel = "[" + o.locale.Input + "]"
+	}
+}
@@ -0,0 +1,11 @@
+//go:build !unix && !windows
+
+package onylogger
+
+import "os"
+
+// Advisory locks are not available; shared files rely on O_APPEND alone.
+
+func lockFile(*os.File) error { return nil }
+
+func unlockFile(*os.File) error { return nil }
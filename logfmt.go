@@ -0,0 +1,82 @@
+package onylogger
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// logfmtFormatter writes entries as logfmt lines:
+//
+//	time=2006-01-02T15:04:05Z level=info msg="request done" status=200
+type logfmtFormatter struct {
+	timestamps *timestampCache
+	fields     FieldFilter
+	order      fieldOrder
+	location   *time.Location // zone of timestamps, local if nil
+}
+
+func newLogfmtFormatter(precision Precision) *logfmtFormatter {
+	return &logfmtFormatter{
+		timestamps: newTimestampCache(time.RFC3339[:19]+precision.fraction()+time.RFC3339[19:], precision.tick()),
+	}
+}
+
+func (f *logfmtFormatter) format(e *Entry, b *bytes.Buffer) ([]byte, error) {
+	if b == nil {
+		b = &bytes.Buffer{}
+	}
+	t := e.Time
+	if f.location != nil {
+		t = t.In(f.location)
+	}
+	b.WriteString("time=")
+	b.Write(f.timestamps.appendTo(nil, t))
+	b.WriteString(" level=")
+	b.WriteString(logfmtValue(e.levelName()))
+	b.WriteString(" msg=")
+	b.WriteString(logfmtValue(plainLinks(e.Message)))
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		if f.fields.keep(k) {
+			keys = append(keys, k)
+		}
+	}
+	f.order.sort(keys)
+	for _, k := range keys {
+		b.WriteByte(' ')
+		b.WriteString(logfmtKey(k))
+		b.WriteByte('=')
+		b.WriteString(logfmtValue(fieldString(e.Fields[k])))
+	}
+	b.WriteByte('\n')
+	return b.Bytes(), nil
+}
+
+// logfmtValue quotes s when it is empty or holds anything that would end
+// a bare value.
+func logfmtValue(s string) string {
+	if s == "" || !utf8.ValidString(s) || strings.IndexFunc(s, func(r rune) bool {
+		return r <= ' ' || r == '=' || r == '"' || r == 0x7f
+	}) >= 0 {
+		return strconv.Quote(s)
+	}
+	return s
+}
+
+// logfmtKey drops from k what a key cannot hold.
+func logfmtKey(k string) string {
+	k = strings.Map(func(r rune) rune {
+		if r <= ' ' || r == '=' || r == '"' || r == 0x7f {
+			return '_'
+		}
+		return r
+	}, k)
+	if k == "" {
+		return "_"
+	}
+	return k
+}
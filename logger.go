@@ -1,98 +1,257 @@
 package onylogger
 
 import (
-	"bufio"
-	"fmt"
+	"errors"
+	"io"
 	"os"
-	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/sirupsen/logrus"
 )
 
 type OnyLogger struct {
 	*logrus.Logger
+
+	backend   backend
+	opts      options
+	render    *renderer
+	stats     *stats
+	collector *errorCollector
+	recent    *ringBuffer
+	async     []*asyncWriter
+	files     []*logFile
+	flushers  []flusher
+	closers   []io.Closer
+	detailed  *atomic.Bool
+	diag      *errorReporter
+	sampler   *sampler
+	gate      *gate
+
+	// Set on loggers derived with Err, which write through root, and on
+	// buffered ones, which write through buffer.
+	root   *OnyLogger
+	fields logrus.Fields
+	buffer *entryBuffer
+
+	closeOnce sync.Once
+	closeErr  error
 }
 
-type emojiFormatter struct {
-	levelEmojis map[logrus.Level]string
+type flusher interface {
+	Flush() error
 }
 
-const (
-	colorReset   = "\033[0m"
-	colorMagenta = "\033[35m"
-	colorYellow  = "\033[33m"
-	colorRed     = "\033[31m"
-	colorCyan    = "\033[36m"
-	colorGreen   = "\033[32m"
-)
+func New(opts ...Option) *OnyLogger {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
 
-func (f *emojiFormatter) Format(entry *logrus.Entry) ([]byte, error) {
-	// Use custom emoji if provided; otherwise use the default for the log level.
-	emoji, ok := entry.Data["emoji"].(string)
-	if !ok {
-		emoji = f.levelEmojis[entry.Level]
+	var formatErr error
+	if name := os.Getenv(formatEnv); name != "" && !o.formatSet {
+		o.format, formatErr = ParseFormat(name)
+	}
+	if !o.styleSet && isTerminal(os.Stderr) && !canRenderEmoji() {
+		o.style = StyleTags
 	}
 
-	var colorCode string
-	switch entry.Level {
-	case logrus.InfoLevel:
-		colorCode = colorMagenta // Magenta for Info
+	log := logrus.New()
+	console := o.newFormatter(o.format, o.style, o.fields, true)
+	text, isText := console.(*emojiFormatter)
+	g := &gate{to: log}
+	log.AddHook(g)
+	diag := &errorReporter{}
+	log.AddHook(lazyHook{report: diag.report})
+	if h, ok := o.processHook(); ok {
+		log.AddHook(h)
+	}
+	if o.service != nil {
+		log.AddHook(newServiceHook(o.service))
+	}
+	if o.kubernetes {
+		if fields := kubernetesFields(); fields != nil {
+			log.AddHook(serviceHook{fields: fields})
+		}
+	}
+	if o.cloud {
+		if fields := cloudInfo(); fields != nil {
+			log.AddHook(serviceHook{fields: fields})
+		}
+	}
+	if o.level != nil {
+		log.SetLevel(*o.level)
+	}
 
-		if logType, exists := entry.Data["log_type"].(string); exists && logType == "input" {
-			colorCode = colorReset // No Color for Input
+	l := &OnyLogger{Logger: log, backend: logrusBackend{log}, opts: o, diag: diag, render: newRenderer(os.Stderr), stats: newStats(), gate: g}
+	diag.render = l.render
+	stages := append([]stage(nil), o.processors...)
+	if len(o.redact) > 0 {
+		stages = append(stages, Processor(o.redact.process).stage())
+	}
+	if o.maxMessage > 0 || o.maxField > 0 {
+		stages = append(stages, Processor(sizeLimits{message: o.maxMessage, field: o.maxField}.process).stage())
+	}
+	if o.breaker != nil {
+		stages = append(stages, Processor(newBreaker(*o.breaker, func(fields Fields, message string) {
+			l.emit(WarnLevel, fields, message)
+		}).process).stage())
+	}
+	if o.sampling != nil {
+		l.sampler = newSampler(*o.sampling, l.emit)
+		stages = append(stages, Processor(l.sampler.process).stage())
+	}
+	if len(stages) > 0 {
+		log.AddHook(pipelineHook{stages: stages})
+	}
+	log.AddHook(l.stats)
+	if isText && o.softWrap {
+		text.columns = l.render.columns
+		l.watchResize()
+	}
+	l.detailed = new(atomic.Bool)
+	l.detailed.Store(detailedFromEnv())
+	if isText {
+		text.detailed = l.detailed
+	}
+	if isText && o.bell {
+		text.bell = true
+		l.render.bell = true
+	}
+	if o.systemdPrefixes {
+		if isText {
+			text.noColor = true
 		}
-	case logrus.WarnLevel:
-		colorCode = colorYellow // Yellow
-	case logrus.ErrorLevel:
-		colorCode = colorRed // Red
-	case logrus.DebugLevel:
-		colorCode = colorCyan // Cyan
-	default:
-		colorCode = colorReset // Default (no color)
+		console = systemdFormatter{next: console}
+	}
+	log.SetFormatter(logrusFormatter{f: console, report: l.reportError})
+	if o.collectErrors {
+		l.collector = &errorCollector{}
+		log.AddHook(l.collector)
+	}
+	if o.recentEntries > 0 {
+		l.recent = newRingBuffer(o.recentEntries)
+		log.AddHook(l.recent)
+	}
+	log.SetOutput(l.wrap(reportingWriter{out: l.render, report: l.reportError, what: "the console"}, o.queue(o.consoleQueue)))
+	if formatErr != nil {
+		l.emitf(ErrorLevel, nil, "Invalid %s: %v", formatEnv, formatErr)
+	}
+	for _, fc := range o.files {
+		l.addFile(fc, &o)
+	}
+	if o.reopenOnSIGHUP {
+		l.handleSIGHUP()
 	}
+	for _, cfg := range o.emails {
+		l.addEmail(cfg)
+	}
+	for _, hc := range o.alerts {
+		l.addAlert(hc)
+	}
+	for _, cfg := range o.notify {
+		l.addDesktopNotify(cfg)
+	}
+	for _, cfg := range o.nats {
+		l.addNATS(cfg, &o)
+	}
+	for _, cfg := range o.redis {
+		l.addRedisStream(cfg, &o)
+	}
+	for _, cfg := range o.mqtt {
+		l.addMQTT(cfg, &o)
+	}
+	for _, cfg := range o.sqlite {
+		l.addSQLite(cfg)
+	}
+	for _, sc := range o.sockets {
+		l.addSocket(sc, &o)
+	}
+	for _, cfg := range o.fluent {
+		l.addFluent(cfg, &o)
+	}
+	for _, cfg := range o.archives {
+		l.addArchive(cfg)
+	}
+	for _, spec := range o.sinks {
+		l.addPluginSink(spec)
+	}
+
+	// Fatal exits the process; make sure queued entries make it out first.
+	log.ExitFunc = func(code int) {
+		l.Close()
+		os.Exit(code)
+	}
+	return l
+}
 
-	// Apply color to the timestamp
-	timestamp := colorCode + entry.Time.Format("2006-01-02 15:04:05") + "\033[0m"
+// textConsole returns the console's text formatter, if it uses one.
+func (l *OnyLogger) textConsole() (*emojiFormatter, bool) {
+	lf, ok := l.Formatter.(logrusFormatter)
+	if !ok {
+		return nil, false
+	}
+	f, ok := lf.f.(*emojiFormatter)
+	return f, ok
+}
 
-	var logMsg strings.Builder
-	logMsg.WriteString("[")
-	logMsg.WriteString(timestamp)
-	logMsg.WriteString("] ")
-	logMsg.WriteString(emoji)
-	logMsg.WriteString(entry.Message)
+// wrap applies the write path of q (async queueing) in front of out; a nil
+// q, or one without room, writes synchronously.
+func (l *OnyLogger) wrap(out io.Writer, q *QueueConfig) io.Writer {
+	if q == nil || q.Size <= 0 {
+		return out
+	}
 
-	// Only add a newline if "no_newline" is not set to true.
-	if noNewline, ok := entry.Data["no_newline"].(bool); !ok || !noNewline {
-		logMsg.WriteString("\n")
+	w := newAsyncWriter(out, *q)
+	w.onResume = func(dropped uint64) {
+		l.emitf(WarnLevel, Fields{"dropped": dropped}, "Dropped %d log entries while the async queue was full", dropped)
 	}
+	l.async = append(l.async, w)
+	l.flushers = append(l.flushers, w)
+	l.closers = append(l.closers, w)
+	return w
+}
 
-	return []byte(logMsg.String()), nil
+// Dropped reports how many entries the async queues have discarded so far.
+func (l *OnyLogger) Dropped() uint64 {
+	var n uint64
+	for _, w := range l.async {
+		n += w.dropped.Load()
+	}
+	return n
 }
 
-func New() *OnyLogger {
-	log := logrus.New()
-	log.SetFormatter(&emojiFormatter{
-		levelEmojis: map[logrus.Level]string{
-			logrus.InfoLevel:  "[📜] ",
-			logrus.WarnLevel:  "[⚠️ ] ",
-			logrus.ErrorLevel: "[❌] ",
-			logrus.DebugLevel: "[🐛] ",
-		},
+// Flush blocks until every buffered or queued entry has been written.
+func (l *OnyLogger) Flush() error {
+	var errs []error
+	for _, f := range l.flushers {
+		errs = append(errs, f.Flush())
+	}
+	return errors.Join(errs...)
+}
+
+// Close flushes and releases everything the logger owns. It is safe to call
+// more than once; entries logged afterwards are written synchronously.
+func (l *OnyLogger) Close() error {
+	if l.root != nil {
+		return l.root.Close()
+	}
+	l.closeOnce.Do(func() {
+		if l.opts.summaryOnClose {
+			l.Summary()
+		}
+
+		var errs []error
+		for i := len(l.closers) - 1; i >= 0; i-- {
+			errs = append(errs, l.closers[i].Close())
+		}
+		l.closeErr = errors.Join(errs...)
 	})
-	return &OnyLogger{Logger: log}
+	return l.closeErr
 }
 
-// LogAndAssignInput logs the provided message with the "📝" emoji without a newline,
-// then reads user input and assigns it to the provided pointer.
-func (l *OnyLogger) Input(message string, userInput *string) {
-	// Chain the WithField calls so both custom fields are set.
-	l.WithField("log_type", "input").
-		WithField("emoji", "[📝] ").
-		WithField("no_newline", true).
-		Info(message)
-	fmt.Print(" ")
-
-	scanner := bufio.NewScanner(os.Stdin)
-	scanner.Scan()
-	*userInput = scanner.Text()
+// SetOutput changes the console destination while keeping entries routed
+// through the render coordinator, so spinners and bars stay intact.
+func (l *OnyLogger) SetOutput(out io.Writer) {
+	l.render.setOutput(out)
 }
@@ -0,0 +1,62 @@
+package onylogger
+
+import (
+	"bytes"
+	"io"
+	"testing"
+	"time"
+)
+
+// newBenchLogger returns a logger writing text to nowhere, the way a
+// console logger does minus the terminal.
+func newBenchLogger() *OnyLogger {
+	l := New(WithLevel(InfoLevel))
+	l.SetOutput(io.Discard)
+	return l
+}
+
+func BenchmarkInfoWithFields(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	for range b.N {
+		l.WithFields(Fields{"user": "ada", "status": 200}).Info("request done")
+	}
+}
+
+func BenchmarkDisabledDebug(b *testing.B) {
+	l := newBenchLogger()
+	b.ReportAllocs()
+	for range b.N {
+		l.Debug("tick")
+		l.Debugfe("[⏱️] ", "tick %d", 1)
+	}
+}
+
+// TestAllocs guards the allocations of the hot paths: a disabled entry
+// must not allocate at all, nor must formatting one into a buffer.
+func TestAllocs(t *testing.T) {
+	l := newBenchLogger()
+	if n := testing.AllocsPerRun(100, func() {
+		l.Debug("tick")
+		l.Debugfe("[⏱️] ", "tick %d", 1)
+	}); n != 0 {
+		t.Errorf("disabled Debug: %v allocs, want 0", n)
+	}
+
+	f := newEmojiFormatter(PrecisionSeconds)
+	e := &Entry{Time: time.Now(), Level: InfoLevel, Message: "request done", Fields: Fields{"status": 200}}
+	var buf bytes.Buffer
+	if n := testing.AllocsPerRun(100, func() {
+		buf.Reset()
+		f.format(e, &buf)
+	}); n != 0 {
+		t.Errorf("Format: %v allocs, want 0", n)
+	}
+
+	const maxInfoAllocs = 12
+	if n := testing.AllocsPerRun(100, func() {
+		l.WithFields(Fields{"user": "ada", "status": 200}).Info("request done")
+	}); n > maxInfoAllocs {
+		t.Errorf("Info with fields: %v allocs, want at most %d", n, maxInfoAllocs)
+	}
+}
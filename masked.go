@@ -0,0 +1,99 @@
+package onylogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// ErrInterrupted is returned by raw-mode prompts when the user presses
+// Ctrl-C, which the terminal does not turn into a signal while they read.
+var ErrInterrupted = errors.New("onylogger: input interrupted")
+
+const (
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyBackspace = 8
+	keyDelete    = 127
+)
+
+// InputMasked prompts for a secret, echoing mask for every character typed
+// (a mask of 0 echoes nothing). Backspace removes the last character. When
+// stdin is not a terminal the answer is read as a plain line.
+func (l *OnyLogger) InputMasked(message string, mask rune) (string, error) {
+	if err := l.prompt(message); err != nil {
+		return "", err
+	}
+	return readMasked(context.Background(), mask)
+}
+
+func readMasked(ctx context.Context, mask rune) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return readLineContext(ctx)
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", err
+	}
+	defer term.Restore(fd, state)
+
+	var answer []rune
+	echo := func(s string) {
+		if mask != 0 {
+			fmt.Print(s)
+		}
+	}
+	for {
+		r, err := readRune(ctx)
+		if err != nil {
+			fmt.Print("\r\n")
+			return "", err
+		}
+		switch r {
+		case '\r', '\n':
+			fmt.Print("\r\n")
+			return string(answer), nil
+		case keyCtrlC:
+			fmt.Print("\r\n")
+			return "", ErrInterrupted
+		case keyCtrlD:
+			if len(answer) == 0 {
+				fmt.Print("\r\n")
+				return "", io.EOF
+			}
+		case keyBackspace, keyDelete:
+			if len(answer) > 0 {
+				answer = answer[:len(answer)-1]
+				echo("\b \b")
+			}
+		default:
+			if r >= ' ' {
+				answer = append(answer, r)
+				echo(string(mask))
+			}
+		}
+	}
+}
+
+// readRune reads one UTF-8 encoded character from stdin.
+func readRune(ctx context.Context) (rune, error) {
+	var b strings.Builder
+	for {
+		c, err := readByteContext(ctx)
+		if err != nil {
+			return 0, err
+		}
+		b.WriteByte(c)
+		if utf8.FullRuneInString(b.String()) {
+			r, _ := utf8.DecodeRuneInString(b.String())
+			return r, nil
+		}
+	}
+}
@@ -0,0 +1,425 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+)
+
+const (
+	mqttConnect   = 0x10
+	mqttConnack   = 0x20
+	mqttPublish   = 0x30
+	mqttPuback    = 0x40
+	mqttPubrec    = 0x50
+	mqttPubrel    = 0x62 // PUBREL carries the mandatory 0b0010 flags
+	mqttPubcomp   = 0x70
+	mqttPingreq   = 0xc0
+	mqttPingresp  = 0xd0
+	mqttTimeout   = 10 * time.Second
+	mqttSpoolSize = 16 << 20
+)
+
+var errMQTTOffline = errors.New("mqtt: broker unreachable")
+
+// MQTTConfig describes the MQTT publisher added with WithMQTT.
+type MQTTConfig struct {
+	Addr     string // host:port, defaults to 127.0.0.1:1883
+	Topic    string
+	QoS      byte   // 0, 1 or 2
+	ClientID string // defaults to "onylogger-<hostname>-<pid>"
+	Username string
+	Password string
+	// TLSConfig, when set, connects over TLS, usually to port 8883; see
+	// NewTLSConfig for client certificates.
+	TLSConfig *tls.Config
+
+	// KeepAlive is the interval announced to the broker and used for
+	// pings, in whole seconds. Defaults to 30 seconds.
+	KeepAlive time.Duration
+	// RetryInterval is how long to wait before dialing again after the
+	// broker could not be reached. Defaults to 5 seconds.
+	RetryInterval time.Duration
+
+	// SpoolFile, when set, collects entries on disk while the broker is
+	// unreachable; they are replayed in order once it is back. Without it,
+	// entries logged while offline are lost.
+	SpoolFile string
+	// SpoolMaxBytes caps the spool file; entries beyond it are dropped.
+	// Defaults to 16 MiB.
+	SpoolMaxBytes int64
+
+	// Queue, when set, overrides WithAsync for this sink.
+	Queue *QueueConfig
+}
+
+// WithMQTT publishes every entry as a JSON message to an MQTT 3.1.1 broker.
+func WithMQTT(cfg MQTTConfig) Option {
+	return func(o *options) {
+		o.mqtt = append(o.mqtt, cfg)
+	}
+}
+
+func (l *OnyLogger) addMQTT(cfg MQTTConfig, o *options) {
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:1883"
+	}
+	if cfg.QoS > 2 {
+		cfg.QoS = 2
+	}
+	if cfg.ClientID == "" {
+		host, _ := os.Hostname()
+		cfg.ClientID = fmt.Sprintf("onylogger-%s-%d", host, os.Getpid())
+	}
+	switch {
+	case cfg.KeepAlive <= 0:
+		cfg.KeepAlive = 30 * time.Second
+	case cfg.KeepAlive < time.Second:
+		cfg.KeepAlive = time.Second // sent in whole seconds
+	}
+	if cfg.RetryInterval == 0 {
+		cfg.RetryInterval = 5 * time.Second
+	}
+	if cfg.SpoolMaxBytes == 0 {
+		cfg.SpoolMaxBytes = mqttSpoolSize
+	}
+
+	w := &mqttWriter{cfg: cfg, stop: make(chan struct{}), done: make(chan struct{})}
+	go w.keepAlive()
+	l.closers = append(l.closers, w)
+	l.addSink("MQTT at "+cfg.Addr, w, newJSONFormatter(o.precision), o.queue(cfg.Queue))
+}
+
+type mqttWriter struct {
+	cfg MQTTConfig
+
+	mu      sync.Mutex
+	conn    net.Conn
+	r       *bufio.Reader
+	nextID  uint16
+	retryAt time.Time
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (w *mqttWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	payload := bytes.TrimRight(p, "\n")
+	if len(payload)+len(w.cfg.Topic)+4 > mqttMaxLength {
+		return 0, fmt.Errorf("mqtt: entry of %d bytes does not fit a packet", len(payload))
+	}
+	if err := w.ensureConnected(); err == nil {
+		if err = w.publish(payload); err == nil {
+			return len(p), nil
+		}
+		w.disconnect()
+	}
+
+	if err := w.spool(payload); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// ensureConnected dials the broker if needed and replays the spool. Must
+// be called with mu held.
+func (w *mqttWriter) ensureConnected() error {
+	if w.conn != nil {
+		return nil
+	}
+	if time.Now().Before(w.retryAt) {
+		return errMQTTOffline
+	}
+
+	if err := w.connect(); err != nil {
+		w.retryAt = time.Now().Add(w.cfg.RetryInterval)
+		return err
+	}
+	if err := w.replay(); err != nil {
+		w.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (w *mqttWriter) connect() error {
+	conn, err := dial("tcp", w.cfg.Addr, mqttTimeout, w.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	w.conn, w.r = conn, bufio.NewReader(conn)
+
+	var flags byte = 0x02 // clean session
+	var payload bytes.Buffer
+	writeMQTTString(&payload, w.cfg.ClientID)
+	if w.cfg.Username != "" {
+		flags |= 0x80
+		writeMQTTString(&payload, w.cfg.Username)
+		if w.cfg.Password != "" {
+			flags |= 0x40
+			writeMQTTString(&payload, w.cfg.Password)
+		}
+	}
+
+	var body bytes.Buffer
+	writeMQTTString(&body, "MQTT")
+	body.WriteByte(4) // protocol level 3.1.1
+	body.WriteByte(flags)
+	binary.Write(&body, binary.BigEndian, uint16(w.cfg.KeepAlive/time.Second))
+	body.Write(payload.Bytes())
+
+	if err := w.writePacket(mqttConnect, body.Bytes()); err != nil {
+		w.disconnect()
+		return err
+	}
+	kind, ack, err := w.readPacket()
+	if err == nil && (kind != mqttConnack || len(ack) != 2) {
+		err = fmt.Errorf("mqtt: expected CONNACK, got packet type %#x", kind)
+	}
+	if err == nil && ack[1] != 0 {
+		err = fmt.Errorf("mqtt: connection refused (code %d)", ack[1])
+	}
+	if err != nil {
+		w.disconnect()
+		return err
+	}
+	return nil
+}
+
+func (w *mqttWriter) disconnect() {
+	if w.conn != nil {
+		w.conn.Close()
+		w.conn, w.r = nil, nil
+	}
+}
+
+func (w *mqttWriter) publish(payload []byte) error {
+	var body bytes.Buffer
+	writeMQTTString(&body, w.cfg.Topic)
+
+	var id uint16
+	if w.cfg.QoS > 0 {
+		w.nextID++
+		if w.nextID == 0 {
+			w.nextID = 1
+		}
+		id = w.nextID
+		binary.Write(&body, binary.BigEndian, id)
+	}
+	body.Write(payload)
+
+	if err := w.writePacket(mqttPublish|w.cfg.QoS<<1, body.Bytes()); err != nil {
+		return err
+	}
+
+	switch w.cfg.QoS {
+	case 1:
+		return w.awaitAck(mqttPuback, id)
+	case 2:
+		if err := w.awaitAck(mqttPubrec, id); err != nil {
+			return err
+		}
+		if err := w.writePacket(mqttPubrel, binary.BigEndian.AppendUint16(nil, id)); err != nil {
+			return err
+		}
+		return w.awaitAck(mqttPubcomp, id)
+	}
+	return nil
+}
+
+// awaitAck reads packets until the acknowledgement of the given type for
+// packet id arrives, skipping ping responses.
+func (w *mqttWriter) awaitAck(kind byte, id uint16) error {
+	for {
+		got, body, err := w.readPacket()
+		if err != nil {
+			return err
+		}
+		if got == mqttPingresp {
+			continue
+		}
+		if got != kind&0xf0 || len(body) < 2 || binary.BigEndian.Uint16(body) != id {
+			return fmt.Errorf("mqtt: unexpected packet type %#x while waiting for %#x", got, kind)
+		}
+		return nil
+	}
+}
+
+func (w *mqttWriter) writePacket(header byte, body []byte) error {
+	packet := appendMQTTLength([]byte{header}, len(body))
+	packet = append(packet, body...)
+
+	w.conn.SetWriteDeadline(time.Now().Add(mqttTimeout))
+	_, err := w.conn.Write(packet)
+	return err
+}
+
+func (w *mqttWriter) readPacket() (byte, []byte, error) {
+	w.conn.SetReadDeadline(time.Now().Add(mqttTimeout))
+
+	header, err := w.r.ReadByte()
+	if err != nil {
+		return 0, nil, err
+	}
+	n, err := readMQTTLength(w.r)
+	if err != nil {
+		return 0, nil, err
+	}
+	body := make([]byte, n)
+	if _, err := io.ReadFull(w.r, body); err != nil {
+		return 0, nil, err
+	}
+	return header & 0xf0, body, nil
+}
+
+// mqttMaxLength is the largest remaining length a packet can have, the
+// most four bytes of it can encode.
+const mqttMaxLength = 1<<28 - 1
+
+var errMQTTLength = errors.New("mqtt: malformed remaining length")
+
+// appendMQTTLength encodes the remaining length of a packet: seven bits
+// per byte, least significant first, the top bit set on all but the last.
+func appendMQTTLength(b []byte, n int) []byte {
+	for {
+		c := byte(n % 128)
+		n /= 128
+		if n > 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+		if n == 0 {
+			return b
+		}
+	}
+}
+
+func readMQTTLength(r *bufio.Reader) (int, error) {
+	var n int
+	for i := 0; i < 4; i++ {
+		c, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		n |= int(c&0x7f) << (7 * i)
+		if c&0x80 == 0 {
+			return n, nil
+		}
+	}
+	return 0, errMQTTLength
+}
+
+func writeMQTTString(b *bytes.Buffer, s string) {
+	binary.Write(b, binary.BigEndian, uint16(len(s)))
+	b.WriteString(s)
+}
+
+// spool keeps an entry on disk until the broker is reachable again.
+func (w *mqttWriter) spool(payload []byte) error {
+	if w.cfg.SpoolFile == "" {
+		return errMQTTOffline
+	}
+
+	f, err := os.OpenFile(w.cfg.SpoolFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if fi, err := f.Stat(); err == nil && fi.Size()+int64(len(payload))+1 > w.cfg.SpoolMaxBytes {
+		return fmt.Errorf("mqtt: spool file %s is full", w.cfg.SpoolFile)
+	}
+	_, err = f.Write(append(payload, '\n'))
+	return err
+}
+
+// replay publishes spooled entries in order. Whatever could not be sent
+// stays in the spool file.
+func (w *mqttWriter) replay() error {
+	if w.cfg.SpoolFile == "" {
+		return nil
+	}
+	data, err := os.ReadFile(w.cfg.SpoolFile)
+	if err != nil || len(data) == 0 {
+		return nil
+	}
+
+	rest := data
+	for len(rest) > 0 {
+		line, next, _ := bytes.Cut(rest, []byte("\n"))
+		if len(line) > 0 {
+			if err := w.publish(line); err != nil {
+				os.WriteFile(w.cfg.SpoolFile, rest, 0o600)
+				return err
+			}
+		}
+		rest = next
+	}
+	return os.Truncate(w.cfg.SpoolFile, 0)
+}
+
+func (w *mqttWriter) keepAlive() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.KeepAlive / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-ticker.C:
+			w.mu.Lock()
+			if w.conn != nil {
+				if err := w.ping(); err != nil {
+					w.disconnect()
+				}
+			} else if w.cfg.SpoolFile != "" {
+				// Replay spooled entries without waiting for the next one.
+				w.ensureConnected()
+			}
+			w.mu.Unlock()
+		}
+	}
+}
+
+// ping sends a PINGREQ and waits for the PINGRESP, so that a broker that
+// stopped answering is noticed even when nothing else is read.
+func (w *mqttWriter) ping() error {
+	if err := w.writePacket(mqttPingreq, nil); err != nil {
+		return err
+	}
+	for {
+		kind, _, err := w.readPacket()
+		if err != nil {
+			return err
+		}
+		if kind == mqttPingresp {
+			return nil
+		}
+	}
+}
+
+func (w *mqttWriter) Close() error {
+	close(w.stop)
+	<-w.done
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != nil {
+		w.writePacket(0xe0, nil) // DISCONNECT
+		w.disconnect()
+	}
+	return nil
+}
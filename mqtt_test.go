@@ -0,0 +1,83 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+// The remaining length examples of the MQTT 3.1.1 specification, 2.2.3.
+func TestMQTTLength(t *testing.T) {
+	tests := []struct {
+		n    int
+		want string
+	}{
+		{0, "00"},
+		{127, "7f"},
+		{128, "8001"},
+		{16383, "ff7f"},
+		{16384, "808001"},
+		{2097151, "ffff7f"},
+		{2097152, "80808001"},
+		{mqttMaxLength, "ffffff7f"},
+	}
+	for _, tt := range tests {
+		got := appendMQTTLength(nil, tt.n)
+		if hex.EncodeToString(got) != tt.want {
+			t.Errorf("appendMQTTLength(%d) = %x, want %s", tt.n, got, tt.want)
+		}
+		n, err := readMQTTLength(bufio.NewReader(bytes.NewReader(got)))
+		if err != nil || n != tt.n {
+			t.Errorf("readMQTTLength(%s) = %d, %v, want %d", tt.want, n, err, tt.n)
+		}
+	}
+
+	if _, err := readMQTTLength(bufio.NewReader(bytes.NewReader([]byte{0xff, 0xff, 0xff, 0xff, 0x01}))); err != errMQTTLength {
+		t.Errorf("five-byte length: got %v, want %v", err, errMQTTLength)
+	}
+}
+
+func TestMQTTPublish(t *testing.T) {
+	payload := strings.Repeat("x", 200)
+	addr, errc := serveOnce(t, func(conn net.Conn, r *bufio.Reader) error {
+		exchanges := []struct{ packet, reply string }{
+			// CONNECT: "MQTT", level 4, clean session with user and
+			// password, keep alive 30s, client ID, user, password.
+			{"1013" + "00044d515454" + "04" + "c2" + "001e" + "000163" + "000175" + "000170", "20020000"},
+			// PUBLISH with QoS 2 and packet ID 1: the 205-byte body takes a
+			// two-byte length.
+			{"34cd01" + "000174" + "0001" + hex.EncodeToString([]byte(payload)), "50020001"},
+			{"62020001", "70020001"}, // PUBREL, PUBCOMP
+			{"e000", ""},             // DISCONNECT
+		}
+		for _, ex := range exchanges {
+			want, _ := hex.DecodeString(ex.packet)
+			if err := expect(r, string(want)); err != nil {
+				return err
+			}
+			reply, _ := hex.DecodeString(ex.reply)
+			if _, err := conn.Write(reply); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	w := &mqttWriter{
+		cfg:  MQTTConfig{Addr: addr, Topic: "t", QoS: 2, ClientID: "c", Username: "u", Password: "p", KeepAlive: 30 * time.Second},
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+	go w.keepAlive()
+	if _, err := w.Write([]byte(payload + "\n")); err != nil {
+		t.Fatal(err)
+	}
+	w.Close()
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
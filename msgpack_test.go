@@ -0,0 +1,134 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Vectors from the MessagePack spec, at each side of every width boundary.
+func TestAppendMsgpack(t *testing.T) {
+	header := func(h string, n int) string { return h + strings.Repeat("78", n) }
+	tests := []struct {
+		name string
+		v    interface{}
+		want string // hex
+	}{
+		{"nil", nil, "c0"},
+		{"false", false, "c2"},
+		{"true", true, "c3"},
+
+		{"positive fixint 0", 0, "00"},
+		{"positive fixint 127", 127, "7f"},
+		{"uint8 128", 128, "cc80"},
+		{"uint8 255", 255, "ccff"},
+		{"uint16 256", 256, "cd0100"},
+		{"uint16 65535", 65535, "cdffff"},
+		{"uint32 65536", 65536, "ce00010000"},
+		{"uint32 max", uint32(math.MaxUint32), "ceffffffff"},
+		{"uint64 2^32", int64(1) << 32, "cf0000000100000000"},
+		{"uint64 max", uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{"negative fixint -1", -1, "ff"},
+		{"negative fixint -32", -32, "e0"},
+		{"int8 -33", -33, "d0df"},
+		{"int8 -128", -128, "d080"},
+		{"int16 -129", -129, "d1ff7f"},
+		{"int16 -32768", -32768, "d18000"},
+		{"int32 -32769", -32769, "d2ffff7fff"},
+		{"int32 min", int32(math.MinInt32), "d280000000"},
+		{"int64 below int32", int64(math.MinInt32) - 1, "d3ffffffff7fffffff"},
+
+		{"float32", float32(1.5), "ca3fc00000"},
+		{"float64", 1.5, "cb3ff8000000000000"},
+
+		{"fixstr 0", "", "a0"},
+		{"fixstr 31", strings.Repeat("x", 31), header("bf", 31)},
+		{"str8 32", strings.Repeat("x", 32), header("d920", 32)},
+		{"str8 255", strings.Repeat("x", 255), header("d9ff", 255)},
+		{"str16 256", strings.Repeat("x", 256), header("da0100", 256)},
+		{"str16 65535", strings.Repeat("x", 65535), header("daffff", 65535)},
+		{"str32 65536", strings.Repeat("x", 65536), header("db00010000", 65536)},
+
+		{"bin8 0", []byte{}, "c400"},
+		{"bin8 255", bytes.Repeat([]byte("x"), 255), header("c4ff", 255)},
+		{"bin16 256", bytes.Repeat([]byte("x"), 256), header("c50100", 256)},
+		{"bin16 65535", bytes.Repeat([]byte("x"), 65535), header("c5ffff", 65535)},
+		{"bin32 65536", bytes.Repeat([]byte("x"), 65536), header("c600010000", 65536)},
+
+		{"map sorted", map[string]interface{}{"b": 1, "a": 2}, "82a16102a16201"},
+		{"array", []interface{}{1, "a", nil}, "9301a161c0"},
+		{"duration", 1500 * time.Millisecond, "a4312e3573"},
+		{"struct via JSON", struct {
+			A int     `json:"a"`
+			B float64 `json:"b"`
+		}{1, 1.5}, "82a16101a162cb3ff8000000000000"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(appendMsgpack(nil, tt.v)); got != tt.want {
+			t.Errorf("%s: got %.40s…, want %.40s…", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestAppendMsgpackHeaders(t *testing.T) {
+	tests := []struct {
+		name string
+		got  []byte
+		want string
+	}{
+		{"fixmap 15", appendMsgpackMapHeader(nil, 15), "8f"},
+		{"map16 16", appendMsgpackMapHeader(nil, 16), "de0010"},
+		{"map16 65535", appendMsgpackMapHeader(nil, 65535), "deffff"},
+		{"map32 65536", appendMsgpackMapHeader(nil, 65536), "df00010000"},
+		{"fixarray 15", appendMsgpackArrayHeader(nil, 15), "9f"},
+		{"array16 16", appendMsgpackArrayHeader(nil, 16), "dc0010"},
+		{"array16 65535", appendMsgpackArrayHeader(nil, 65535), "dcffff"},
+		{"array32 65536", appendMsgpackArrayHeader(nil, 65536), "dd00010000"},
+		{"event time", appendEventTime(nil, time.Unix(1, 2)), "d7000000000100000002"},
+	}
+	for _, tt := range tests {
+		if got := hex.EncodeToString(tt.got); got != tt.want {
+			t.Errorf("%s: got %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestReadMsgpackStringMap(t *testing.T) {
+	long := strings.Repeat("x", 300)
+	tests := []struct {
+		name string
+		in   []byte
+		want map[string]string
+	}{
+		{"fixmap of fixstr", appendMsgpack(nil, map[string]interface{}{"ack": "abc"}), map[string]string{"ack": "abc"}},
+		{"str8", appendMsgpack(nil, map[string]interface{}{"ack": strings.Repeat("y", 40)}), map[string]string{"ack": strings.Repeat("y", 40)}},
+		{"str16", appendMsgpack(nil, map[string]interface{}{"ack": long}), map[string]string{"ack": long}},
+		{"bin8", append(append(appendMsgpackMapHeader(nil, 1), "\xa3ack"...), appendMsgpackBinary(nil, []byte("abc"))...), map[string]string{"ack": "abc"}},
+		{"map16", append(appendMsgpackMapHeader(nil, 16)[:1], 0x00, 0x00), map[string]string{}},
+	}
+	for _, tt := range tests {
+		got, err := readMsgpackStringMap(bufio.NewReader(bytes.NewReader(tt.in)))
+		if err != nil {
+			t.Errorf("%s: %v", tt.name, err)
+			continue
+		}
+		if len(got) != len(tt.want) {
+			t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+		}
+		for k, v := range tt.want {
+			if got[k] != v {
+				t.Errorf("%s: got %v, want %v", tt.name, got, tt.want)
+			}
+		}
+	}
+
+	for _, in := range []string{"\x91\xa0", "\x81\x01\xa0", "\x81\xa1a", ""} {
+		if _, err := readMsgpackStringMap(bufio.NewReader(strings.NewReader(in))); err == nil {
+			t.Errorf("%q: no error", in)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package onylogger
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// InputMultiline prompts for a block of text, such as a pasted certificate,
+// read line by line until a line equal to terminator or the end of input
+// (Ctrl-D). The block is returned with every line ending in "\n" and
+// without the terminator line; an empty terminator only ends at Ctrl-D.
+func (l *OnyLogger) InputMultiline(message, terminator string) (string, error) {
+	hint := "(end with Ctrl-D)"
+	if terminator != "" {
+		hint = "(end with a line containing " + terminator + ")"
+	}
+	if err := l.prompt(message + " " + hint); err != nil {
+		return "", err
+	}
+	fmt.Println()
+
+	var lines []string
+	for {
+		line, err := readLineContext(context.Background())
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		if terminator != "" && line == terminator {
+			break
+		}
+		lines = append(lines, line)
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	return strings.Join(lines, "\n") + "\n", nil
+}
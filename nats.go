@@ -0,0 +1,167 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// NATSConfig describes the NATS publisher added with WithNATS.
+type NATSConfig struct {
+	Addr    string // host:port, defaults to 127.0.0.1:4222
+	Subject string
+
+	User     string
+	Password string
+	Token    string
+	// TLSConfig, when set, upgrades the connection to TLS as the server
+	// greets; see NewTLSConfig for client certificates.
+	TLSConfig *tls.Config
+
+	// Queue, when set, overrides WithAsync for this sink.
+	Queue *QueueConfig
+}
+
+// WithNATS publishes every entry as a JSON message on a NATS subject. The
+// connection is (re)established lazily on the next entry after a failure.
+func WithNATS(cfg NATSConfig) Option {
+	return func(o *options) {
+		o.nats = append(o.nats, cfg)
+	}
+}
+
+func (l *OnyLogger) addNATS(cfg NATSConfig, o *options) {
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:4222"
+	}
+	w := &natsWriter{cfg: cfg}
+	l.closers = append(l.closers, w)
+	l.addSink("NATS at "+cfg.Addr, w, newJSONFormatter(o.precision), o.queue(cfg.Queue))
+}
+
+// natsWriter speaks just enough of the NATS client protocol to publish:
+// CONNECT, PUB, and answering the server's PINGs.
+type natsWriter struct {
+	cfg NATSConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+}
+
+func (w *natsWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	payload := bytes.TrimRight(p, "\n")
+	w.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	_, err := fmt.Fprintf(w.conn, "PUB %s %d\r\n%s\r\n", w.cfg.Subject, len(payload), payload)
+	if err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// connect dials the server and performs the handshake. Must be called with
+// mu held.
+func (w *natsWriter) connect() error {
+	conn, err := dial("tcp", w.cfg.Addr, 10*time.Second, nil)
+	if err != nil {
+		return err
+	}
+
+	r := bufio.NewReader(conn)
+	conn.SetReadDeadline(time.Now().Add(10 * time.Second))
+	info, err := r.ReadString('\n')
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	if !strings.HasPrefix(info, "INFO ") {
+		conn.Close()
+		return fmt.Errorf("nats: unexpected greeting %q", strings.TrimSpace(info))
+	}
+	conn.SetReadDeadline(time.Time{})
+	if w.cfg.TLSConfig != nil {
+		// NATS servers greet in the clear and switch to TLS after INFO.
+		tlsConn, err := startTLS(conn, w.cfg.Addr, 10*time.Second, w.cfg.TLSConfig)
+		if err != nil {
+			conn.Close()
+			return err
+		}
+		conn, r = tlsConn, bufio.NewReader(tlsConn)
+	}
+
+	opts := map[string]interface{}{
+		"verbose":  false,
+		"pedantic": false,
+		"name":     "onylogger",
+		"lang":     "go",
+	}
+	if w.cfg.User != "" {
+		opts["user"], opts["pass"] = w.cfg.User, w.cfg.Password
+	}
+	if w.cfg.Token != "" {
+		opts["auth_token"] = w.cfg.Token
+	}
+	connect, _ := json.Marshal(opts)
+	conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := fmt.Fprintf(conn, "CONNECT %s\r\n", connect); err != nil {
+		conn.Close()
+		return err
+	}
+
+	w.conn = conn
+	go w.readLoop(conn, r)
+	return nil
+}
+
+// readLoop answers PINGs and drops the connection on protocol errors so
+// the next write reconnects.
+func (w *natsWriter) readLoop(conn net.Conn, r *bufio.Reader) {
+	for {
+		line, err := r.ReadString('\n')
+		if err == nil && strings.HasPrefix(line, "PING") {
+			w.mu.Lock()
+			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+			_, err = conn.Write([]byte("PONG\r\n"))
+			w.mu.Unlock()
+		} else if err == nil && strings.HasPrefix(line, "-ERR") {
+			err = fmt.Errorf("nats: %s", strings.TrimSpace(line))
+		}
+		if err != nil {
+			w.mu.Lock()
+			if w.conn == conn {
+				w.conn.Close()
+				w.conn = nil
+			}
+			w.mu.Unlock()
+			return
+		}
+	}
+}
+
+func (w *natsWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
@@ -0,0 +1,99 @@
+package onylogger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"testing"
+)
+
+// serveOnce accepts one connection on a local port and runs serve on it,
+// returning the address and the error serve returned.
+func serveOnce(t *testing.T, serve func(conn net.Conn, r *bufio.Reader) error) (string, <-chan error) {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { ln.Close() })
+
+	errc := make(chan error, 1)
+	go func() {
+		conn, err := ln.Accept()
+		if err != nil {
+			errc <- err
+			return
+		}
+		defer conn.Close()
+		errc <- serve(conn, bufio.NewReader(conn))
+	}()
+	return ln.Addr().String(), errc
+}
+
+// expect reads len(want) bytes from r and compares them with want.
+func expect(r *bufio.Reader, want string) error {
+	got := make([]byte, len(want))
+	if _, err := io.ReadFull(r, got); err != nil {
+		return err
+	}
+	if string(got) != want {
+		return fmt.Errorf("got %q, want %q", got, want)
+	}
+	return nil
+}
+
+func TestNATSPublish(t *testing.T) {
+	addr, errc := serveOnce(t, func(conn net.Conn, r *bufio.Reader) error {
+		if _, err := io.WriteString(conn, "INFO {\"server_id\":\"test\"}\r\n"); err != nil {
+			return err
+		}
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		connect, ok := strings.CutPrefix(line, "CONNECT ")
+		if !ok || !strings.HasSuffix(connect, "\r\n") {
+			return fmt.Errorf("got %q, want CONNECT", line)
+		}
+		var opts map[string]interface{}
+		if err := json.Unmarshal([]byte(connect), &opts); err != nil {
+			return err
+		}
+		if opts["user"] != "u" || opts["pass"] != "p" || opts["verbose"] != false {
+			return fmt.Errorf("CONNECT options %v", opts)
+		}
+		// The size is in bytes, not runes.
+		if err := expect(r, "PUB logs 15\r\n{\"msg\":\"héé\"}\r\n"); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(conn, "PING\r\n"); err != nil {
+			return err
+		}
+		return expect(r, "PONG\r\n")
+	})
+
+	w := &natsWriter{cfg: NATSConfig{Addr: addr, Subject: "logs", User: "u", Password: "p"}}
+	defer w.Close()
+	if _, err := w.Write([]byte("{\"msg\":\"héé\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNATSBadGreeting(t *testing.T) {
+	addr, _ := serveOnce(t, func(conn net.Conn, r *bufio.Reader) error {
+		_, err := io.WriteString(conn, "-ERR 'Authorization Violation'\r\n")
+		return err
+	})
+
+	w := &natsWriter{cfg: NATSConfig{Addr: addr, Subject: "logs"}}
+	defer w.Close()
+	if _, err := w.Write([]byte("{}\n")); err == nil {
+		t.Fatal("no error")
+	}
+}
@@ -0,0 +1,161 @@
+package onylogger
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const defaultNotifyCooldown = 10 * time.Second
+
+// DesktopNotifyConfig tunes the hook added with WithDesktopNotifications.
+type DesktopNotifyConfig struct {
+	// Title of the notifications; defaults to the executable name.
+	Title string
+	// Level is the least severe level that notifies; defaults to Error
+	// when nil.
+	Level *Level
+	// Cooldown is the minimum time between notifications; entries in
+	// between are counted in the next one. Defaults to ten seconds.
+	Cooldown time.Duration
+}
+
+// WithDesktopNotifications shows a desktop notification for Error and worse
+// entries, using notify-send on Linux and BSD, osascript on macOS and a
+// toast on Windows. Meant for local tools whose terminal is in the
+// background; a missing notifier is reported once and then ignored.
+func WithDesktopNotifications(cfg DesktopNotifyConfig) Option {
+	return func(o *options) {
+		o.notify = append(o.notify, cfg)
+	}
+}
+
+type notifyHook struct {
+	cfg    DesktopNotifyConfig
+	levels []logrus.Level
+	queue  chan string
+	done   chan struct{}
+	report func(error)
+
+	mu         sync.Mutex
+	last       time.Time
+	suppressed int
+
+	closeMu sync.RWMutex
+	closed  bool
+}
+
+func (l *OnyLogger) addDesktopNotify(cfg DesktopNotifyConfig) {
+	if cfg.Title == "" {
+		cfg.Title = filepath.Base(os.Args[0])
+	}
+	level := logrus.ErrorLevel
+	if cfg.Level != nil {
+		level = min(*cfg.Level, logrus.TraceLevel)
+	}
+	if cfg.Cooldown == 0 {
+		cfg.Cooldown = defaultNotifyCooldown
+	}
+
+	h := &notifyHook{cfg: cfg, levels: logrus.AllLevels[:level+1], queue: make(chan string, 4), done: make(chan struct{}), report: l.reportError}
+	go h.run()
+	l.closers = append(l.closers, h)
+	l.AddHook(h)
+}
+
+func (h *notifyHook) Levels() []logrus.Level {
+	return h.levels
+}
+
+func (h *notifyHook) Fire(entry *logrus.Entry) error {
+	h.closeMu.RLock()
+	defer h.closeMu.RUnlock()
+	if h.closed || demoted(entry, h.Levels()) {
+		return nil
+	}
+
+	h.mu.Lock()
+	if entry.Time.Sub(h.last) < h.cfg.Cooldown {
+		h.suppressed++
+		h.mu.Unlock()
+		return nil
+	}
+	h.last = entry.Time
+	more := h.suppressed
+	h.suppressed = 0
+	h.mu.Unlock()
+
+	msg := fmt.Sprintf("%s: %s", levelTag(entry.Level), plainLinks(entry.Message))
+	if more > 0 {
+		msg += fmt.Sprintf(" (+%d more)", more)
+	}
+	select {
+	case h.queue <- msg:
+	default: // the notifier is stuck; never hold up logging for it
+	}
+	return nil
+}
+
+func (h *notifyHook) run() {
+	defer close(h.done)
+	var failed bool
+	for msg := range h.queue {
+		if err := desktopNotify(h.cfg.Title, msg); err != nil && !failed {
+			failed = true
+			h.report(fmt.Errorf("failed to show desktop notification: %w", err))
+		}
+	}
+}
+
+func desktopNotify(title, message string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, message)
+	case "windows":
+		cmd = exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "ONYLOGGER_TITLE="+title, "ONYLOGGER_MESSAGE="+message)
+	default:
+		cmd = exec.Command("notify-send", "--app-name", title, "--urgency", "critical", title, message)
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("%s: %v %s", cmd.Path, err, out)
+	}
+	return nil
+}
+
+// windowsToastScript shows a toast with the title and message passed in the
+// environment, which saves quoting them for PowerShell.
+const windowsToastScript = `
+[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$xml = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $xml.GetElementsByTagName('text')
+$text.Item(0).AppendChild($xml.CreateTextNode($env:ONYLOGGER_TITLE)) > $null
+$text.Item(1).AppendChild($xml.CreateTextNode($env:ONYLOGGER_MESSAGE)) > $null
+$toast = [Windows.UI.Notifications.ToastNotification]::new($xml)
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('PowerShell').Show($toast)
+`
+
+// Close waits for pending notifications to be shown.
+func (h *notifyHook) Close() error {
+	h.closeMu.Lock()
+	defer h.closeMu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+
+	close(h.queue)
+	<-h.done
+	return nil
+}
@@ -0,0 +1,155 @@
+package onylogger
+
+import (
+	"os"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Option configures an OnyLogger at construction time.
+type Option func(*options)
+
+type options struct {
+	async     *QueueConfig
+	files     []*fileConfig
+	emails    []EmailConfig
+	alerts    []alertHookConfig
+	nats      []NATSConfig
+	redis     []RedisStreamConfig
+	mqtt      []MQTTConfig
+	sqlite    []SQLiteConfig
+	sockets   []*socketConfig
+	fluent    []FluentConfig
+	archives  []ArchiveConfig
+	notify    []DesktopNotifyConfig
+	style     Style
+	styleSet  bool
+	format    Format
+	formatSet bool
+	timeMode  timeMode
+	precision Precision
+	level     *logrus.Level
+
+	summaryOnClose  bool
+	collectErrors   bool
+	exitCodes       map[logrus.Level]int
+	reopenOnSIGHUP  bool
+	crashDir        string
+	recentEntries   int
+	pid             bool
+	goroutineID     bool
+	sequence        bool
+	service         *serviceInfo
+	fields          FieldFilter
+	fieldOrder      fieldOrder
+	nonInteractive  NonInteractivePolicy
+	softWrap        bool
+	bell            bool
+	systemdPrefixes bool
+	verbosity       *int
+	slowStacks      bool
+	redact          redactionRules
+	locale          *Locale
+	location        *time.Location
+	dualTime        bool
+	colorRules      []ColorRule
+	highlight       []*regexp.Regexp
+	rawControl      bool
+	maxMessage      int
+	maxField        int
+	breaker         *BreakerConfig
+	sampling        *SamplingConfig
+	consoleQueue    *QueueConfig
+	sinks           []sinkSpec
+	processors      []stage
+	kubernetes      bool
+	cloud           bool
+}
+
+// WithLevel sets the minimum level that is logged.
+func WithLevel(level Level) Option {
+	return func(o *options) {
+		o.level = &level
+	}
+}
+
+// WithAsync moves writing off the calling goroutine: formatted entries are
+// queued (up to size) and written by a background worker. The policy decides
+// what happens to new entries once the queue is full. It applies to every
+// output without a QueueConfig of its own.
+func WithAsync(size int, policy BackpressurePolicy) Option {
+	return func(o *options) {
+		if size < 1 {
+			size = 1
+		}
+		o.async = &QueueConfig{Size: size, Policy: policy}
+	}
+}
+
+// WithConsoleQueue sets how console output is queued, overriding WithAsync.
+func WithConsoleQueue(q QueueConfig) Option {
+	return func(o *options) {
+		o.consoleQueue = &q
+	}
+}
+
+// queue returns the queue of an output: its own, if set, or WithAsync's.
+func (o *options) queue(own *QueueConfig) *QueueConfig {
+	if own != nil {
+		return own
+	}
+	return o.async
+}
+
+// newFormatter builds the formatter for one output. Console output is
+// colored and honors the console-only timestamp modes; sinks are plain.
+func (o *options) newFormatter(format Format, style Style, fields FieldFilter, console bool) entryFormatter {
+	switch {
+	case format == FormatJSON || format == FormatLogstash || format == FormatECS:
+		f := newJSONFormatter(o.precision)
+		f.fields = fields
+		f.order = o.fieldOrder
+		f.logstash = format == FormatLogstash
+		f.ecs = format == FormatECS
+		return f
+	case format == FormatLogfmt:
+		f := newLogfmtFormatter(o.precision)
+		f.fields = fields
+		f.order = o.fieldOrder
+		return f
+	case format >= firstCustomFormat:
+		if f, ok := newCustomFormatter(format); ok {
+			return f
+		}
+	}
+
+	f := newEmojiFormatter(o.precision)
+	f.style = style
+	f.fields = fields
+	f.order = o.fieldOrder
+	f.dimFields = o.processFields()
+	o.applyLocale(f)
+	f.rawControl = o.rawControl
+	if format == FormatDocker {
+		f.noColor = true
+		if console {
+			return &dockerFormatter{text: f, stream: "stderr"}
+		}
+		return &dockerFormatter{text: f, stream: "stdout"}
+	}
+	if console {
+		f.timeMode = o.timeMode
+		f.location = o.location
+		f.colorRules = o.colorRules
+		f.highlights = o.highlight
+		if o.dualTime {
+			f.localClock = newTimestampCache("15:04:05"+o.precision.fraction()+" MST", o.precision.tick())
+		}
+		f.links = isTerminal(os.Stderr) && supportsHyperlinks()
+	} else {
+		f.noColor = true
+	}
+	return f
+}
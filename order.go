@@ -0,0 +1,85 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithFieldOrder renders the listed fields first, in the given order, and
+// all others alphabetically after them, in text and JSON outputs alike:
+//
+//	onylogger.WithFieldOrder("component", "request_id")
+//
+// The entry's own JSON keys ("time", "level", "msg") can be listed too.
+// Without it JSON keys and text fields are sorted alphabetically.
+func WithFieldOrder(keys ...string) Option {
+	return func(o *options) {
+		o.fieldOrder = newFieldOrder(keys)
+	}
+}
+
+// fieldOrder maps priority keys to their rank.
+type fieldOrder map[string]int
+
+func newFieldOrder(keys []string) fieldOrder {
+	order := make(fieldOrder, len(keys))
+	for i, k := range keys {
+		if _, ok := order[k]; !ok {
+			order[k] = i
+		}
+	}
+	return order
+}
+
+func (order fieldOrder) sort(keys []string) {
+	sort.Slice(keys, func(i, j int) bool {
+		ri, iok := order[keys[i]]
+		rj, jok := order[keys[j]]
+		switch {
+		case iok && jok:
+			return ri < rj
+		case iok != jok:
+			return iok
+		}
+		return keys[i] < keys[j]
+	})
+}
+
+// encodeOrdered writes data as a JSON object with its keys in order, like
+// encoding/json would with sorted keys.
+func encodeOrdered(b *bytes.Buffer, data logrus.Fields, order fieldOrder) error {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	order.sort(keys)
+
+	var value bytes.Buffer
+	enc := json.NewEncoder(&value)
+	enc.SetEscapeHTML(false)
+
+	b.WriteByte('{')
+	for i, k := range keys {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		value.Reset()
+		if err := enc.Encode(k); err != nil {
+			return err
+		}
+		if err := enc.Encode(data[k]); err != nil {
+			return err
+		}
+		// Encode ends every value with a newline: drop them.
+		kv := value.Bytes()
+		nl := bytes.IndexByte(kv, '\n')
+		b.Write(kv[:nl])
+		b.WriteByte(':')
+		b.Write(bytes.TrimSuffix(kv[nl+1:], []byte("\n")))
+	}
+	b.WriteString("}\n")
+	return nil
+}
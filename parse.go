@@ -0,0 +1,288 @@
+package onylogger
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Parser reads entries back from what a file sink wrote, in the text, JSON
+// or Logstash format. Text fields come back as strings, since their type is
+// not written out, and only with ParseTextFields; JSON numbers come back as
+// json.Number.
+//
+//	p := onylogger.NewParser(f)
+//	for {
+//		e, err := p.Next()
+//		if err == io.EOF {
+//			break
+//		}
+//		...
+//	}
+type Parser struct {
+	scanner    *bufio.Scanner
+	line       int
+	pending    *Entry // text entry whose continuation lines may follow
+	next       *Entry // JSON entry read while returning pending
+	textFields bool
+	location   *time.Location
+}
+
+// ParserOption tunes a Parser.
+type ParserOption func(*Parser)
+
+// ParseTextFields splits the key=value pairs at the end of text messages
+// into fields, for files written with fields shown (see FileFields). By
+// default they stay part of the message, since text files show no fields
+// and a message may well end in "host=db" itself.
+func ParseTextFields() ParserOption {
+	return func(p *Parser) {
+		p.textFields = true
+	}
+}
+
+// ParseLocation sets the zone of text timestamps, for files written with
+// FileTimezone. Defaults to the local zone.
+func ParseLocation(loc *time.Location) ParserOption {
+	return func(p *Parser) {
+		p.location = loc
+	}
+}
+
+// NewParser returns a parser reading from r.
+func NewParser(r io.Reader, opts ...ParserOption) *Parser {
+	s := bufio.NewScanner(r)
+	s.Buffer(nil, 64<<20)
+	p := &Parser{scanner: s, location: time.Local}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// ParseFile reads all entries of a log file, decompressing rotated files
+// that end in .gz.
+func ParseFile(path string, opts ...ParserOption) ([]Entry, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".gz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gz.Close()
+		r = gz
+	}
+
+	var entries []Entry
+	p := NewParser(r, opts...)
+	for {
+		e, err := p.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return entries, err
+		}
+		entries = append(entries, e)
+	}
+}
+
+// Next returns the next entry, or io.EOF after the last one. Lines that
+// do not start an entry continue the message of the one before, the way
+// multi-line messages are written; any before the first entry are skipped.
+func (p *Parser) Next() (Entry, error) {
+	if e := p.next; e != nil {
+		p.next = nil
+		return *e, nil
+	}
+	for p.scanner.Scan() {
+		p.line++
+		line := p.scanner.Text()
+
+		if strings.HasPrefix(line, "{") {
+			e, err := parseJSONEntry(line)
+			if err != nil {
+				return Entry{}, fmt.Errorf("line %d: %w", p.line, err)
+			}
+			if prev := p.takePending(); prev != nil {
+				p.next = &e
+				return *prev, nil
+			}
+			return e, nil
+		}
+
+		e, ok := parseTextEntry(line, p.location)
+		if !ok {
+			if p.pending != nil {
+				p.pending.Message += "\n" + line
+			}
+			continue
+		}
+		prev := p.takePending()
+		p.pending = &e
+		if prev != nil {
+			return *prev, nil
+		}
+	}
+	if err := p.scanner.Err(); err != nil {
+		return Entry{}, err
+	}
+	if prev := p.takePending(); prev != nil {
+		return *prev, nil
+	}
+	return Entry{}, io.EOF
+}
+
+// takePending returns the text entry being collected, with the fields
+// written after its last line split off if asked to.
+func (p *Parser) takePending() *Entry {
+	e := p.pending
+	if e == nil {
+		return nil
+	}
+	p.pending = nil
+	if p.textFields {
+		e.Message = splitTextFields(e.Message, e.Fields)
+	}
+	return e
+}
+
+func parseJSONEntry(line string) (Entry, error) {
+	dec := json.NewDecoder(strings.NewReader(line))
+	dec.UseNumber()
+	var data map[string]interface{}
+	if err := dec.Decode(&data); err != nil {
+		return Entry{}, err
+	}
+
+	e := Entry{Fields: make(logrus.Fields, len(data))}
+	for k, v := range data {
+		switch k {
+		case "time", "@timestamp":
+			s, _ := v.(string)
+			e.Time, _ = time.Parse(time.RFC3339Nano, s)
+		case "level", "log.level":
+			s, _ := v.(string)
+			e.Level, _ = logrus.ParseLevel(s)
+		case "msg", "message":
+			e.Message, _ = v.(string)
+		case "@version", "ecs.version":
+		default:
+			e.Fields[strings.TrimPrefix(k, "fields.")] = v
+		}
+	}
+	return e, nil
+}
+
+// textLevels maps the level emoji of the text format back to their level.
+var textLevels = map[string]logrus.Level{
+	"[📜]":   logrus.InfoLevel,
+	"[⚠️ ]": logrus.WarnLevel,
+	"[❌]":   logrus.ErrorLevel,
+	"[🐛]":   logrus.DebugLevel,
+	"[💥]":   logrus.FatalLevel,
+}
+
+// parseTextEntry parses the first line of a text entry:
+//
+//	[2006-01-02 15:04:05] pid=1 [WARN] [⚠️ ] message key=value
+//
+// The process fields, level tag and emoji are each optional. Entries with
+// neither a tag nor an emoji are Trace, the one level shown without one;
+// custom emoji are taken to be Info. Timestamps are in loc.
+func parseTextEntry(line string, loc *time.Location) (Entry, bool) {
+	end := strings.Index(line, "] ")
+	if !strings.HasPrefix(line, "[") || end < 0 {
+		return Entry{}, false
+	}
+	t, err := time.ParseInLocation(timestampLayout, line[1:end], loc)
+	if err != nil {
+		return Entry{}, false
+	}
+	e := Entry{Time: t, Level: logrus.InfoLevel, Fields: make(logrus.Fields)}
+	rest := line[end+2:]
+
+	for _, k := range []string{"pid", "goroutine", "seq"} {
+		if !strings.HasPrefix(rest, k+"=") {
+			continue
+		}
+		v, after, _ := strings.Cut(rest[len(k)+1:], " ")
+		e.Fields[k] = v
+		rest = after
+	}
+
+	var tagged bool
+	for level, tag := range levelTags {
+		if strings.HasPrefix(rest, tag+" ") {
+			e.Level, tagged = logrus.Level(level), true
+			rest = rest[len(tag)+1:]
+			break
+		}
+	}
+	if end := strings.Index(rest, "] "); strings.HasPrefix(rest, "[") && end > 0 && isEmoji(rest[1:end]) {
+		if level, ok := textLevels[rest[:end+1]]; ok && !tagged {
+			e.Level = level
+		}
+		rest = rest[end+2:]
+	} else if !tagged {
+		e.Level = logrus.TraceLevel
+	}
+	e.Message = rest
+	return e, true
+}
+
+// trailingField matches a " key=value" pair at the end of a message.
+var trailingField = regexp.MustCompile(`\s([^\s="]+)=("(?:[^"\\]|\\.)*"|[^\s="]+)$`)
+
+// splitTextFields moves the key=value pairs at the end of message into
+// fields and returns the message without them.
+func splitTextFields(message string, fields logrus.Fields) string {
+	for {
+		m := trailingField.FindStringSubmatchIndex(message)
+		if m == nil {
+			return message
+		}
+		key, value := message[m[2]:m[3]], message[m[4]:m[5]]
+		if strings.HasPrefix(value, `"`) {
+			unquoted, err := strconv.Unquote(value)
+			if err != nil {
+				return message
+			}
+			value = unquoted
+		}
+		if _, dup := fields[key]; !dup {
+			fields[key] = value
+		}
+		message = message[:m[0]]
+	}
+}
+
+// isEmoji tells an emoji between brackets from a message that happens to
+// start with a bracket.
+func isEmoji(s string) bool {
+	if len(s) > 16 {
+		return false
+	}
+	for _, r := range s {
+		if r >= utf8.RuneSelf {
+			return true
+		}
+	}
+	return false
+}
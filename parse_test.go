@@ -0,0 +1,26 @@
+package onylogger
+
+import (
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// FuzzParseTextEntry checks that no line makes the text parser panic.
+func FuzzParseTextEntry(f *testing.F) {
+	for _, seed := range []string{
+		"",
+		"2024-01-02 15:04:05 [INFO] started port=8080",
+		"2024-01-02 15:04:05 [ERROR] \xff\xfe bad=\xc3",
+		"\x1b[31m2024-01-02 15:04:05\x1b[0m [WARN] colored",
+		"2024-01-02 15:04:05 [DEBUG] overwrite\rthis key=\"unterminated",
+		"key= =value ==",
+	} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, line string) {
+		parseTextEntry(line, time.Local)
+		splitTextFields(line, logrus.Fields{})
+	})
+}
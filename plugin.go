@@ -0,0 +1,265 @@
+package onylogger
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Sink is a destination added from outside the package, e.g. a ClickHouse
+// table. Write is called for every entry, one at a time, on the goroutine
+// that logged it; an error it returns is reported (see SetErrorHandler).
+// Flush is called by the logger's Flush and Close by its Close.
+type Sink interface {
+	Write(e Entry) error
+	Flush() error
+	Close() error
+}
+
+// SinkFactory builds a sink from its settings, such as the keys of a sink
+// entry in a config file.
+type SinkFactory func(settings map[string]string) (Sink, error)
+
+var (
+	sinkFactoriesMu sync.RWMutex
+	sinkFactories   = make(map[string]SinkFactory)
+)
+
+// RegisterSink makes a kind of sink available by name to
+// WithRegisteredSink and NewSink, usually from the init function of the
+// package implementing it. It panics if name is already registered.
+func RegisterSink(name string, factory SinkFactory) {
+	sinkFactoriesMu.Lock()
+	defer sinkFactoriesMu.Unlock()
+	if _, dup := sinkFactories[name]; dup {
+		panic("onylogger: RegisterSink called twice for " + name)
+	}
+	sinkFactories[name] = factory
+}
+
+// Sinks returns the names of the registered sinks, sorted.
+func Sinks() []string {
+	sinkFactoriesMu.RLock()
+	defer sinkFactoriesMu.RUnlock()
+	names := make([]string, 0, len(sinkFactories))
+	for name := range sinkFactories {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// NewSink builds a sink of the kind registered as name.
+func NewSink(name string, settings map[string]string) (Sink, error) {
+	sinkFactoriesMu.RLock()
+	factory, ok := sinkFactories[name]
+	sinkFactoriesMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("onylogger: unknown sink %q", name)
+	}
+	return factory(settings)
+}
+
+// WithSink adds s as an output.
+func WithSink(s Sink) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sinkSpec{sink: s})
+	}
+}
+
+// WithRegisteredSink adds a sink of the kind registered as name, built
+// from settings. If that fails, the error is logged and the logger is
+// created without it.
+//
+//	onylogger.WithRegisteredSink("clickhouse", map[string]string{"dsn": dsn})
+func WithRegisteredSink(name string, settings map[string]string) Option {
+	return func(o *options) {
+		o.sinks = append(o.sinks, sinkSpec{name: name, settings: settings})
+	}
+}
+
+type sinkSpec struct {
+	sink     Sink
+	name     string
+	settings map[string]string
+}
+
+func (l *OnyLogger) addPluginSink(spec sinkSpec) {
+	s, name := spec.sink, spec.name
+	if s == nil {
+		var err error
+		if s, err = NewSink(name, spec.settings); err != nil {
+			l.emitf(ErrorLevel, nil, "Failed to add sink %s: %v", name, err)
+			return
+		}
+	} else {
+		name = fmt.Sprintf("%T", s)
+	}
+
+	h := &pluginSink{sink: s, name: name, report: l.reportError}
+	l.flushers = append(l.flushers, h)
+	l.closers = append(l.closers, h)
+	l.AddHook(h)
+}
+
+// pluginSink feeds a Sink from a logrus hook, serializing its calls.
+type pluginSink struct {
+	sink   Sink
+	name   string
+	report func(error)
+
+	mu     sync.Mutex
+	closed bool
+}
+
+func (h *pluginSink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *pluginSink) Fire(entry *logrus.Entry) error {
+	if demoted(entry, h.Levels()) {
+		return nil
+	}
+	e := newEntry(entry)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	if err := h.sink.Write(e); err != nil {
+		h.report(fmt.Errorf("failed to write to sink %s: %w", h.name, err))
+	}
+	return nil
+}
+
+func (h *pluginSink) Flush() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	return h.sink.Flush()
+}
+
+func (h *pluginSink) Close() error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	h.closed = true
+	return h.sink.Close()
+}
+
+// Formatter renders an entry for an output, trailing newline included.
+// One is built per output, by the factory given to RegisterFormatter, and
+// it is never called concurrently.
+type Formatter interface {
+	Format(e Entry) ([]byte, error)
+}
+
+// formatNames are the names of the built-in formats, by Format.
+var formatNames = []string{
+	FormatText:     "text",
+	FormatJSON:     "json",
+	FormatDocker:   "docker",
+	FormatLogstash: "logstash",
+	FormatLogfmt:   "logfmt",
+	FormatECS:      "ecs",
+}
+
+// firstCustomFormat is the Format of the first registered formatter.
+var firstCustomFormat = Format(len(formatNames))
+
+type customFormat struct {
+	name    string
+	factory func() Formatter
+}
+
+var (
+	customFormatsMu sync.RWMutex
+	customFormats   []customFormat
+)
+
+// RegisterFormatter makes a formatter available by name to ParseFormat,
+// and so to config files, the ONYLOGGER_FORMAT variable and the
+// --log-format flag, returning the Format that selects it. It panics if
+// name is already taken.
+//
+//	var FormatGELF = onylogger.RegisterFormatter("gelf", newGELFFormatter)
+//	...
+//	onylogger.New(onylogger.WithFormat(FormatGELF))
+func RegisterFormatter(name string, factory func() Formatter) Format {
+	customFormatsMu.Lock()
+	defer customFormatsMu.Unlock()
+	if _, err := parseFormat(name); err == nil {
+		panic("onylogger: RegisterFormatter called twice for " + name)
+	}
+	customFormats = append(customFormats, customFormat{name: name, factory: factory})
+	return firstCustomFormat + Format(len(customFormats)-1)
+}
+
+// ParseFormat returns the format called name: "text", "json", "docker",
+// "logstash", "logfmt", "ecs", or one added with RegisterFormatter.
+func ParseFormat(name string) (Format, error) {
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+	return parseFormat(name)
+}
+
+// parseFormat is ParseFormat. Must be called with customFormatsMu held.
+func parseFormat(name string) (Format, error) {
+	for f, n := range formatNames {
+		if n == name {
+			return Format(f), nil
+		}
+	}
+	for i, c := range customFormats {
+		if c.name == name {
+			return firstCustomFormat + Format(i), nil
+		}
+	}
+	return 0, fmt.Errorf("onylogger: unknown log format %q", name)
+}
+
+// String returns the name of the format.
+func (f Format) String() string {
+	if f >= 0 && f < firstCustomFormat {
+		return formatNames[f]
+	}
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+	if i := int(f - firstCustomFormat); i >= 0 && i < len(customFormats) {
+		return customFormats[i].name
+	}
+	return fmt.Sprintf("Format(%d)", int(f))
+}
+
+// customFormatter hands entries to a registered Formatter, one at a time.
+type customFormatter struct {
+	mu sync.Mutex
+	f  Formatter
+}
+
+func newCustomFormatter(format Format) (*customFormatter, bool) {
+	customFormatsMu.RLock()
+	defer customFormatsMu.RUnlock()
+	i := int(format - firstCustomFormat)
+	if i < 0 || i >= len(customFormats) {
+		return nil, false
+	}
+	return &customFormatter{f: customFormats[i].factory()}, true
+}
+
+func (c *customFormatter) format(e *Entry, _ *bytes.Buffer) ([]byte, error) {
+	entry := *e
+	entry.Message = plainLinks(entry.Message)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.f.Format(entry)
+}
@@ -0,0 +1,162 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxPrettyJSON is how much of a value JSON shows before truncating it.
+const maxPrettyJSON = 16 << 10
+
+// JSON logs v as indented JSON under label, with keys matching the
+// WithRedaction patterns hidden at any depth. Output over 16 KiB is cut at
+// a line boundary. The console colors the keys.
+//
+//	log.JSON(onylogger.DebugLevel, "Webhook payload", payload)
+func (l *OnyLogger) JSON(level Level, label string, v interface{}) {
+	if !l.enabled(level) {
+		return
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Failed to encode %s as JSON: %v", label, err)
+		return
+	}
+
+	var b bytes.Buffer
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := writePrettyJSON(&b, dec, 0, l.opts.redact); err != nil {
+		l.emitf(ErrorLevel, nil, "Failed to encode %s as JSON: %v", label, err)
+		return
+	}
+
+	text := b.String()
+	if len(text) > maxPrettyJSON {
+		cut := strings.LastIndexByte(text[:maxPrettyJSON], '\n')
+		if cut < 0 {
+			cut = maxPrettyJSON
+			for cut > 0 && !utf8.RuneStart(text[cut]) {
+				cut--
+			}
+		}
+		text = fmt.Sprintf("%s\n… (%d more bytes)", text[:cut], len(text)-cut)
+	}
+	l.emit(level, Fields{"log_type": "json"}, label+"\n"+text)
+}
+
+// writePrettyJSON re-encodes the next value of dec with two-space
+// indentation, keeping the order of object keys.
+func writePrettyJSON(b *bytes.Buffer, dec *json.Decoder, depth int, rules redactionRules) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return writeJSONToken(b, tok)
+	}
+
+	closing := byte(']')
+	if delim == '{' {
+		closing = '}'
+	}
+	b.WriteByte(byte(delim))
+	var n int
+	for ; dec.More(); n++ {
+		if n > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteByte('\n')
+		b.WriteString(strings.Repeat("  ", depth+1))
+
+		if delim == '{' {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			if err := writeJSONToken(b, key); err != nil {
+				return err
+			}
+			b.WriteString(": ")
+			if k, _ := key.(string); rules.match(k) {
+				if err := skipJSONValue(dec); err != nil {
+					return err
+				}
+				b.WriteString(`"` + redacted + `"`)
+				continue
+			}
+		}
+		if err := writePrettyJSON(b, dec, depth+1, rules); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+	if n > 0 {
+		b.WriteByte('\n')
+		b.WriteString(strings.Repeat("  ", depth))
+	}
+	b.WriteByte(closing)
+	return nil
+}
+
+func writeJSONToken(b *bytes.Buffer, tok json.Token) error {
+	if n, ok := tok.(json.Number); ok {
+		b.WriteString(n.String())
+		return nil
+	}
+	enc := json.NewEncoder(b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(tok); err != nil {
+		return err
+	}
+	b.Truncate(b.Len() - 1) // Encode adds a newline
+	return nil
+}
+
+func skipJSONValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
+
+// jsonKey matches the key at the start of a line of indented JSON.
+var jsonKey = regexp.MustCompile(`^(\s*)("(?:[^"\\]|\\.)*")(: )`)
+
+// writeJSON writes a JSON message, coloring its keys on the console.
+func (f *emojiFormatter) writeJSON(b *bytes.Buffer, message string) {
+	for i, line := range strings.Split(message, "\n") {
+		if i > 0 {
+			b.WriteByte('\n')
+		}
+		m := jsonKey.FindStringSubmatchIndex(line)
+		if i == 0 || m == nil {
+			b.WriteString(line)
+			continue
+		}
+		b.WriteString(line[:m[3]])
+		f.startColor(b, colorCyan)
+		b.WriteString(line[m[4]:m[5]])
+		f.endColor(b)
+		b.WriteString(line[m[5]:])
+	}
+}
@@ -0,0 +1,103 @@
+package onylogger
+
+import (
+	"bytes"
+	"os"
+	"runtime"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithPID stamps every entry with the process ID in a "pid" field.
+func WithPID() Option {
+	return func(o *options) {
+		o.pid = true
+	}
+}
+
+// WithGoroutineID stamps every entry with the ID of the goroutine that
+// logged it in a "goroutine" field. Go does not expose goroutine IDs as an
+// API, so this reads them from the stack trace header; it costs about a
+// microsecond per entry.
+func WithGoroutineID() Option {
+	return func(o *options) {
+		o.goroutineID = true
+	}
+}
+
+// WithSequence stamps every entry with a number in a "seq" field that goes
+// up by one for each entry the process logs, across loggers, so outputs can
+// be merged back in order when timestamps collide.
+func WithSequence() Option {
+	return func(o *options) {
+		o.sequence = true
+	}
+}
+
+// sequence numbers entries for WithSequence.
+var sequence atomic.Uint64
+
+// processHook adds the fields requested with WithPID, WithGoroutineID and
+// WithSequence.
+// Hooks fire on the logging goroutine, which is what makes the goroutine ID
+// meaningful.
+type processHook struct {
+	pid       int
+	goroutine bool
+	sequence  bool
+}
+
+func (h processHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h processHook) Fire(entry *logrus.Entry) error {
+	if h.pid != 0 {
+		entry.Data["pid"] = h.pid
+	}
+	if h.goroutine {
+		entry.Data["goroutine"] = goroutineID()
+	}
+	if h.sequence {
+		entry.Data["seq"] = sequence.Add(1)
+	}
+	return nil
+}
+
+// goroutineID parses the current goroutine's ID from "goroutine 17 [running]:".
+func goroutineID() uint64 {
+	var buf [64]byte
+	b := buf[:runtime.Stack(buf[:], false)]
+	b = bytes.TrimPrefix(b, []byte("goroutine "))
+	if i := bytes.IndexByte(b, ' '); i > 0 {
+		b = b[:i]
+	}
+	id, _ := strconv.ParseUint(string(b), 10, 64)
+	return id
+}
+
+// processFields lists the fields the text formatter shows dimmed in front
+// of the message.
+func (o *options) processFields() []string {
+	var fields []string
+	if o.pid {
+		fields = append(fields, "pid")
+	}
+	if o.goroutineID {
+		fields = append(fields, "goroutine")
+	}
+	if o.sequence {
+		fields = append(fields, "seq")
+	}
+	return fields
+}
+
+func (o *options) processHook() (processHook, bool) {
+	h := processHook{goroutine: o.goroutineID, sequence: o.sequence}
+	if o.pid {
+		h.pid = os.Getpid()
+	}
+	return h, o.pid || o.goroutineID || o.sequence
+}
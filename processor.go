@@ -0,0 +1,141 @@
+package onylogger
+
+import (
+	"context"
+
+	"github.com/sirupsen/logrus"
+)
+
+// dropLevel is the level given to entries a processor dropped: past
+// TraceLevel, no logger logs it, so the hooks that feed outputs pass over
+// them (see demoted).
+const dropLevel = TraceLevel + 1
+
+// Processor is a step of the pipeline every entry goes through before it
+// is formatted and handed to the outputs. It returns the entry to log,
+// changed or not, or false to drop it. Processors run on the goroutine
+// that logged the entry, so they must be safe for concurrent use.
+//
+// Redaction (WithRedaction), size limits (WithSizeLimits), the error
+// breaker (WithErrorBreaker) and sampling (WithAdaptiveSampling) are
+// processors themselves, and run after those added with WithProcessor and
+// WithEnricher.
+type Processor func(e Entry) (Entry, bool)
+
+// stage is a step of the pipeline, given the context the entry was logged
+// with (see logrus's WithContext), or context.Background.
+type stage func(ctx context.Context, e Entry) (Entry, bool)
+
+func (p Processor) stage() stage {
+	return func(_ context.Context, e Entry) (Entry, bool) {
+		return p(e)
+	}
+}
+
+// WithProcessor adds processors to the pipeline, in order.
+//
+//	onylogger.WithProcessor(func(e onylogger.Entry) (onylogger.Entry, bool) {
+//		return e, e.Fields["path"] != "/healthz"
+//	})
+func WithProcessor(processors ...Processor) Option {
+	return func(o *options) {
+		for _, p := range processors {
+			o.processors = append(o.processors, p.stage())
+		}
+	}
+}
+
+// WithEnricher adds fields computed for every entry from the context it
+// was logged with, e.g. a tenant ID, so that the call sites do not have to.
+// Fields the entry already has are kept. Entries logged without a context
+// get context.Background.
+//
+//	onylogger.WithEnricher(func(ctx context.Context) onylogger.Fields {
+//		return onylogger.Fields{"tenant": tenantFrom(ctx)}
+//	})
+//	...
+//	log.WithContext(ctx).Info("order placed")
+func WithEnricher(enrich func(ctx context.Context) Fields) Option {
+	return func(o *options) {
+		o.processors = append(o.processors, func(ctx context.Context, e Entry) (Entry, bool) {
+			for k, v := range enrich(ctx) {
+				if _, ok := e.Fields[k]; !ok {
+					e.Fields[k] = v
+				}
+			}
+			return e, true
+		})
+	}
+}
+
+// pipelineHook runs the processors on every entry.
+type pipelineHook struct {
+	stages []stage
+}
+
+func (pipelineHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h pipelineHook) Fire(entry *logrus.Entry) error {
+	if entry.Level == dropLevel {
+		return nil // set aside by the gate, see Transaction
+	}
+	ctx := entry.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	e := entryFrom(entry)
+	for _, s := range h.stages {
+		var keep bool
+		if e, keep = s(ctx, e); !keep {
+			entry.Level = dropLevel
+			return nil
+		}
+	}
+	e.writeTo(entry)
+	return nil
+}
+
+// writeTo stores e back into the logrus entry it came from, control fields
+// included.
+func (e *Entry) writeTo(entry *logrus.Entry) {
+	entry.Time, entry.Level, entry.Message, entry.Caller = e.Time, e.Level, e.Message, e.Caller
+
+	data := make(logrus.Fields, len(e.Fields)+1)
+	for k, v := range e.Fields {
+		data[k] = v
+	}
+	if e.Stack != "" {
+		data["stack"] = e.Stack
+	}
+	if e.hasEmoji {
+		data["emoji"] = e.emoji
+	}
+	if e.logType != "" {
+		data["log_type"] = e.logType
+	}
+	if e.noNewline {
+		data["no_newline"] = true
+	}
+	if e.custom != nil {
+		data["custom_level"] = e.custom
+	}
+	entry.Data = data
+}
+
+// demoted reports whether a hook firing at levels should pass over entry,
+// because a processor lowered its level to one that is not logged or that
+// levels leave out, or dropped it. logrus picks the hooks to fire from the
+// level the entry had when it was logged.
+func demoted(entry *logrus.Entry, levels []logrus.Level) bool {
+	if entry.Logger != nil && !entry.Logger.IsLevelEnabled(entry.Level) {
+		return true
+	}
+	for _, level := range levels {
+		if level == entry.Level {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,63 @@
+package onylogger
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Filter selects entries for Query. Zero fields match everything.
+type Filter struct {
+	// Level is the least severe level matched; the zero value, PanicLevel,
+	// is taken to mean all levels.
+	Level Level
+	// Since and Until bound the entry time, inclusive.
+	Since, Until time.Time
+	// Fields must all be present with these values, compared as printed.
+	Fields Fields
+	// Message, when set, must match the message.
+	Message *regexp.Regexp
+	// Limit, when non-zero, keeps only the most recent matches.
+	Limit int
+}
+
+// Query returns the entries kept in memory (see WithViewer) that match f,
+// oldest first. It returns nil if no entries are kept.
+//
+//	errs := log.Query(onylogger.Filter{Level: onylogger.ErrorLevel, Since: time.Now().Add(-time.Hour)})
+func (l *OnyLogger) Query(f Filter) []Entry {
+	if l.recent == nil {
+		return nil
+	}
+	var matched []Entry
+	for _, e := range l.recent.snapshot() {
+		if f.match(e) {
+			matched = append(matched, e)
+		}
+	}
+	if f.Limit > 0 && len(matched) > f.Limit {
+		matched = matched[len(matched)-f.Limit:]
+	}
+	return matched
+}
+
+func (f Filter) match(e Entry) bool {
+	if f.Level != logrus.PanicLevel && e.Level > f.Level {
+		return false
+	}
+	if !f.Since.IsZero() && e.Time.Before(f.Since) {
+		return false
+	}
+	if !f.Until.IsZero() && e.Time.After(f.Until) {
+		return false
+	}
+	for k, want := range f.Fields {
+		got, ok := e.Fields[k]
+		if !ok || fmt.Sprint(got) != fmt.Sprint(want) {
+			return false
+		}
+	}
+	return f.Message == nil || f.Message.MatchString(e.Message)
+}
@@ -0,0 +1,44 @@
+package onylogger
+
+import (
+	"path"
+	"strings"
+)
+
+// redacted replaces the values of redacted keys.
+const redacted = "[REDACTED]"
+
+// WithRedaction hides the values of fields whose keys match one of the
+// patterns, and of matching keys in values logged with JSON. Patterns are
+// matched case-insensitively with path.Match, so "*token*" hides
+// "AccessToken" and "refresh_token".
+func WithRedaction(patterns ...string) Option {
+	return func(o *options) {
+		for _, p := range patterns {
+			o.redact = append(o.redact, strings.ToLower(p))
+		}
+	}
+}
+
+type redactionRules []string
+
+func (r redactionRules) match(key string) bool {
+	key = strings.ToLower(key)
+	for _, p := range r {
+		if ok, _ := path.Match(p, key); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// process replaces redacted field values before any formatter or sink sees
+// the entry.
+func (r redactionRules) process(e Entry) (Entry, bool) {
+	for k := range e.Fields {
+		if r.match(k) {
+			e.Fields[k] = redacted
+		}
+	}
+	return e, true
+}
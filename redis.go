@@ -0,0 +1,144 @@
+package onylogger
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// RedisStreamConfig describes the Redis Streams publisher added with
+// WithRedisStream.
+type RedisStreamConfig struct {
+	Addr     string // host:port, defaults to 127.0.0.1:6379
+	Password string
+	DB       int
+	Stream   string
+	// TLSConfig, when set, connects over TLS; see NewTLSConfig for client
+	// certificates.
+	TLSConfig *tls.Config
+
+	// MaxLen caps the stream length (approximately, with "MAXLEN ~").
+	// Zero leaves the stream unbounded.
+	MaxLen int
+
+	// Queue, when set, overrides WithAsync for this sink.
+	Queue *QueueConfig
+}
+
+// WithRedisStream appends every entry to a Redis stream with XADD, as a
+// JSON document in the "entry" field.
+func WithRedisStream(cfg RedisStreamConfig) Option {
+	return func(o *options) {
+		o.redis = append(o.redis, cfg)
+	}
+}
+
+func (l *OnyLogger) addRedisStream(cfg RedisStreamConfig, o *options) {
+	if cfg.Addr == "" {
+		cfg.Addr = "127.0.0.1:6379"
+	}
+	w := &redisStreamWriter{cfg: cfg}
+	l.closers = append(l.closers, w)
+	l.addSink("Redis at "+cfg.Addr, w, newJSONFormatter(o.precision), o.queue(cfg.Queue))
+}
+
+// redisStreamWriter issues one XADD per entry over a plain RESP connection.
+type redisStreamWriter struct {
+	cfg RedisStreamConfig
+
+	mu   sync.Mutex
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func (w *redisStreamWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if err := w.connect(); err != nil {
+			return 0, err
+		}
+	}
+
+	args := []string{"XADD", w.cfg.Stream}
+	if w.cfg.MaxLen > 0 {
+		args = append(args, "MAXLEN", "~", strconv.Itoa(w.cfg.MaxLen))
+	}
+	args = append(args, "*", "entry", string(bytes.TrimRight(p, "\n")))
+
+	if err := w.do(args...); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// connect dials and authenticates. Must be called with mu held.
+func (w *redisStreamWriter) connect() error {
+	conn, err := dial("tcp", w.cfg.Addr, 10*time.Second, w.cfg.TLSConfig)
+	if err != nil {
+		return err
+	}
+	w.conn, w.r = conn, bufio.NewReader(conn)
+
+	if w.cfg.Password != "" {
+		err = w.do("AUTH", w.cfg.Password)
+	}
+	if err == nil && w.cfg.DB != 0 {
+		err = w.do("SELECT", strconv.Itoa(w.cfg.DB))
+	}
+	if err != nil {
+		conn.Close()
+		w.conn = nil
+		return err
+	}
+	return nil
+}
+
+// do sends one command and reads its reply, returning server errors.
+func (w *redisStreamWriter) do(args ...string) error {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&b, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+
+	w.conn.SetDeadline(time.Now().Add(10 * time.Second))
+	if _, err := w.conn.Write(b.Bytes()); err != nil {
+		return err
+	}
+
+	line, err := w.r.ReadString('\n')
+	if err != nil {
+		return err
+	}
+	switch line[0] {
+	case '-':
+		return fmt.Errorf("redis: %s", bytes.TrimSpace([]byte(line[1:])))
+	case '$':
+		// Bulk reply, e.g. the ID XADD assigned; skip its payload.
+		if n, _ := strconv.Atoi(string(bytes.TrimSpace([]byte(line[1:])))); n >= 0 {
+			_, err = w.r.Discard(n + 2)
+		}
+	}
+	return err
+}
+
+func (w *redisStreamWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
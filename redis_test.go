@@ -0,0 +1,45 @@
+package onylogger
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestRedisXADD(t *testing.T) {
+	addr, errc := serveOnce(t, func(conn net.Conn, r *bufio.Reader) error {
+		replies := []struct{ command, reply string }{
+			{"*2\r\n$4\r\nAUTH\r\n$2\r\npw\r\n", "+OK\r\n"},
+			{"*2\r\n$6\r\nSELECT\r\n$1\r\n2\r\n", "+OK\r\n"},
+			// Bulk lengths are in bytes, not runes.
+			{"*8\r\n$4\r\nXADD\r\n$4\r\nlogs\r\n$6\r\nMAXLEN\r\n$1\r\n~\r\n$3\r\n100\r\n$1\r\n*\r\n$5\r\nentry\r\n$15\r\n{\"msg\":\"héé\"}\r\n",
+				"$15\r\n1526919030474-0\r\n"},
+			{"*8\r\n$4\r\nXADD\r\n$4\r\nlogs\r\n$6\r\nMAXLEN\r\n$1\r\n~\r\n$3\r\n100\r\n$1\r\n*\r\n$5\r\nentry\r\n$2\r\n{}\r\n",
+				"-WRONGTYPE Operation against a key holding the wrong kind of value\r\n"},
+		}
+		for _, rr := range replies {
+			if err := expect(r, rr.command); err != nil {
+				return err
+			}
+			if _, err := io.WriteString(conn, rr.reply); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	w := &redisStreamWriter{cfg: RedisStreamConfig{Addr: addr, Password: "pw", DB: 2, Stream: "logs", MaxLen: 100}}
+	defer w.Close()
+	if _, err := w.Write([]byte("{\"msg\":\"héé\"}\n")); err != nil {
+		t.Fatal(err)
+	}
+	// The bulk reply is consumed whole: the next reply is read in step.
+	_, err := w.Write([]byte("{}\n"))
+	if err == nil || err.Error() != "redis: WRONGTYPE Operation against a key holding the wrong kind of value" {
+		t.Errorf("got %v, want the WRONGTYPE error", err)
+	}
+	if err := <-errc; err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,204 @@
+package onylogger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/term"
+)
+
+// widget is an animated, single-line element (spinner, progress bar) that
+// stays pinned below the regular log output while it is active.
+type widget interface {
+	render() string
+}
+
+// renderer is the central frame writer for the console. Every entry goes
+// through it so that active widgets are erased, the entry is printed, and
+// the widgets are redrawn underneath in one locked step.
+type renderer struct {
+	mu      sync.Mutex
+	out     io.Writer
+	tty     bool
+	plain   bool // a regular file, written without escape sequences
+	widgets []widget
+	drawn   int  // widget lines currently on screen
+	bell    bool // entries may end in a bell for the terminal
+
+	// While held, e.g. by the viewer owning the screen, entries are kept in
+	// held and written out on release.
+	holding bool
+	held    []byte
+	omitted int // entries that did not fit in held
+
+	cols     atomic.Int64 // terminal width, 0 if out is not a terminal
+	measured atomic.Int64 // when cols was measured, in Unix nanoseconds
+}
+
+func newRenderer(out io.Writer) *renderer {
+	r := &renderer{}
+	r.setOutput(out)
+	return r
+}
+
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	return ok && term.IsTerminal(int(f.Fd()))
+}
+
+func (r *renderer) setOutput(out io.Writer) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	r.out = out
+	r.tty = isTerminal(out)
+	r.plain = isRegularFile(out)
+	r.measure()
+	r.draw()
+}
+
+func (r *renderer) Write(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.holding {
+		if len(r.held)+len(p) > maxHeldOutput {
+			r.omitted++
+		} else {
+			r.held = append(r.held, p...)
+		}
+		return len(p), nil
+	}
+
+	r.clear()
+	n, err := r.writeEntry(p)
+	r.draw()
+	return n, err
+}
+
+// writeEntry writes p, leaving out the bell when out is not a terminal and
+// escape sequences when it is a regular file. Must be called with mu held.
+func (r *renderer) writeEntry(p []byte) (int, error) {
+	if (!r.bell || r.tty) && !r.plain {
+		return r.out.Write(p)
+	}
+	trimmed := p
+	if r.bell && !r.tty {
+		trimmed = stripBell(trimmed)
+	}
+	if r.plain {
+		trimmed, _ = stripANSI(trimmed)
+	}
+	n, err := r.out.Write(trimmed)
+	if err == nil && n == len(trimmed) {
+		n = len(p)
+	}
+	return n, err
+}
+
+// maxHeldOutput bounds the console output kept while the renderer is held.
+const maxHeldOutput = 1 << 20
+
+// hold hands the screen to the caller: widgets are erased and entries are
+// kept back until release.
+func (r *renderer) hold() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	r.holding = true
+}
+
+// release writes out the entries kept back by hold and redraws the widgets.
+func (r *renderer) release() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.holding = false
+	r.writeEntry(r.held)
+	if r.omitted > 0 {
+		fmt.Fprintf(r.out, "(%d more entries were logged while the console was in use)\n", r.omitted)
+	}
+	r.held, r.omitted = nil, 0
+	r.draw()
+}
+
+// paint writes s to the console while it is held.
+func (r *renderer) paint(s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	io.WriteString(r.out, s)
+}
+
+// writeAside writes s to w, which may share the terminal with the console,
+// with the widgets erased around it.
+func (r *renderer) writeAside(w io.Writer, s string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	io.WriteString(w, s)
+	r.draw()
+}
+
+func (r *renderer) add(w widget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	r.widgets = append(r.widgets, w)
+	r.draw()
+}
+
+func (r *renderer) remove(w widget) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	for i, existing := range r.widgets {
+		if existing == w {
+			r.widgets = append(r.widgets[:i], r.widgets[i+1:]...)
+			break
+		}
+	}
+	r.draw()
+}
+
+// refresh redraws the widgets in place, e.g. on an animation tick.
+func (r *renderer) refresh() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.clear()
+	r.draw()
+}
+
+// clear erases the widget lines drawn last time. Must be called with mu held.
+func (r *renderer) clear() {
+	if r.drawn == 0 {
+		return
+	}
+	fmt.Fprintf(r.out, "\033[%dA\r\033[J", r.drawn)
+	r.drawn = 0
+}
+
+// draw prints all active widgets below the cursor. Must be called with mu held.
+// Widgets are only animated on a terminal; anywhere else they stay silent and
+// only their final log lines are written.
+func (r *renderer) draw() {
+	if !r.tty || r.holding {
+		return
+	}
+	for _, w := range r.widgets {
+		io.WriteString(r.out, w.render()+"\n")
+		r.drawn++
+	}
+}
@@ -0,0 +1,64 @@
+package onylogger
+
+import (
+	"errors"
+	"os"
+	"os/signal"
+)
+
+// WithReopenOnSIGHUP calls ReopenFiles whenever the process receives
+// SIGHUP, which is what logrotate's "postrotate kill -HUP" expects. It does
+// nothing on systems without SIGHUP.
+func WithReopenOnSIGHUP() Option {
+	return func(o *options) {
+		o.reopenOnSIGHUP = true
+	}
+}
+
+// ReopenFiles flushes and closes every file sink and opens its path again,
+// so that files moved away by an external tool are released and writing
+// continues in a fresh file.
+func (l *OnyLogger) ReopenFiles() error {
+	errs := []error{l.Flush()}
+	for _, lf := range l.files {
+		errs = append(errs, lf.reopen())
+	}
+	return errors.Join(errs...)
+}
+
+func (lf *logFile) reopen() error {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	// A failed close is no reason to keep writing to the old file.
+	closeErr := lf.f.Close()
+	return errors.Join(closeErr, lf.open())
+}
+
+// sighupHandler runs ReopenFiles on SIGHUP until closed.
+type sighupHandler struct {
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+func (l *OnyLogger) handleSIGHUP() {
+	h := &sighupHandler{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	notifyHangup(h.signals)
+
+	go func() {
+		defer close(h.done)
+		for range h.signals {
+			if err := l.ReopenFiles(); err != nil {
+				l.emitf(ErrorLevel, nil, "Failed to reopen log files: %v", err)
+			}
+		}
+	}()
+	l.closers = append(l.closers, h)
+}
+
+func (h *sighupHandler) Close() error {
+	signal.Stop(h.signals)
+	close(h.signals)
+	<-h.done
+	return nil
+}
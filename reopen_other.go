@@ -0,0 +1,8 @@
+//go:build !unix
+
+package onylogger
+
+import "os"
+
+// Without SIGHUP, WithReopenOnSIGHUP does nothing; call ReopenFiles instead.
+func notifyHangup(chan<- os.Signal) {}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package onylogger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+func notifyHangup(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGHUP)
+}
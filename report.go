@@ -0,0 +1,173 @@
+package onylogger
+
+import (
+	"errors"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// HTMLReportOptions tunes WriteHTMLReport.
+type HTMLReportOptions struct {
+	// Title of the page; defaults to the executable name.
+	Title string
+	// Filter selects the entries included; the zero Filter includes all.
+	Filter Filter
+	// Entries, when set, are reported instead of those kept in memory, e.g.
+	// entries read back with ParseFile.
+	Entries []Entry
+}
+
+// WriteHTMLReport renders entries into a self-contained HTML page with
+// level colors, a level filter and a search box. Multi-line messages, like
+// stack traces, are collapsed to their first line. Without opts.Entries it
+// reports the entries kept in memory (see WithViewer).
+func (l *OnyLogger) WriteHTMLReport(w io.Writer, opts HTMLReportOptions) error {
+	entries := opts.Entries
+	if entries == nil {
+		if l.recent == nil {
+			return errors.New("onylogger: no entries are kept in memory, see WithViewer")
+		}
+		entries = l.Query(opts.Filter)
+	} else {
+		var matched []Entry
+		for _, e := range entries {
+			if opts.Filter.match(e) {
+				matched = append(matched, e)
+			}
+		}
+		entries = matched
+	}
+	if opts.Title == "" {
+		opts.Title = filepath.Base(os.Args[0])
+	}
+
+	data := htmlReport{Title: opts.Title, Generated: time.Now().Format(time.RFC1123)}
+	seen := make(map[logrus.Level]bool)
+	for _, e := range entries {
+		seen[e.Level] = true
+		data.Entries = append(data.Entries, newHTMLEntry(e))
+	}
+	for _, level := range logrus.AllLevels {
+		if seen[level] {
+			data.Levels = append(data.Levels, level.String())
+		}
+	}
+	return reportTemplate.Execute(w, data)
+}
+
+type htmlReport struct {
+	Title     string
+	Generated string
+	Levels    []string
+	Entries   []htmlEntry
+}
+
+type htmlEntry struct {
+	Time    string
+	Level   string
+	Summary string // the first line of the message
+	Detail  string // the rest, collapsed
+	Fields  []htmlField
+}
+
+type htmlField struct {
+	Key, Value string
+	Error      bool
+}
+
+func newHTMLEntry(e Entry) htmlEntry {
+	summary, detail, _ := strings.Cut(e.Message, "\n")
+	h := htmlEntry{
+		Time:    e.Time.Format("2006-01-02 15:04:05.000"),
+		Level:   e.Level.String(),
+		Summary: summary,
+		Detail:  detail,
+	}
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		_, isErr := e.Fields[k].(error)
+		h.Fields = append(h.Fields, htmlField{Key: k, Value: fieldString(e.Fields[k]), Error: isErr})
+	}
+	return h
+}
+
+var reportTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body { font: 13px/1.4 ui-monospace, SFMono-Regular, Menlo, Consolas, monospace; margin: 1.5em; color: #222; }
+header { margin-bottom: 1em; }
+h1 { font-size: 1.4em; margin: 0 0 .2em; }
+.meta { color: #777; }
+.controls { margin: .8em 0; }
+.controls label { margin-right: 1em; }
+.controls input[type=search] { width: 24em; }
+table { border-collapse: collapse; width: 100%; }
+td { padding: .2em .6em; vertical-align: top; border-bottom: 1px solid #eee; }
+td.time { white-space: nowrap; color: #777; }
+td.level { white-space: nowrap; font-weight: bold; }
+summary { cursor: pointer; }
+pre { margin: .3em 0 0; white-space: pre-wrap; }
+.field { color: #777; margin-left: .8em; }
+.field .error { color: #c00; }
+tr.panic .level, tr.fatal .level, tr.error .level { color: #c00; }
+tr.warning .level { color: #b8860b; }
+tr.info .level { color: #a0a; }
+tr.debug .level { color: #088; }
+tr.trace .level { color: #777; }
+tr.hidden { display: none; }
+</style>
+</head>
+<body>
+<header>
+<h1>{{.Title}}</h1>
+<div class="meta">{{len .Entries}} entries, generated {{.Generated}}</div>
+</header>
+<div class="controls">
+{{range .Levels}}<label><input type="checkbox" class="level-toggle" value="{{.}}" checked> {{.}}</label>{{end}}
+<input type="search" id="search" placeholder="Search">
+</div>
+<table>
+{{range .Entries}}<tr class="{{.Level}}" data-level="{{.Level}}">
+<td class="time">{{.Time}}</td>
+<td class="level">{{.Level}}</td>
+<td class="message">{{if .Detail}}<details><summary>{{.Summary}}</summary><pre>{{.Detail}}</pre></details>{{else}}{{.Summary}}{{end}}
+{{- range .Fields}}<span class="field">{{.Key}}=<span{{if .Error}} class="error"{{end}}>{{.Value}}</span></span>{{end}}</td>
+</tr>
+{{end}}</table>
+<script>
+(function () {
+	var rows = document.querySelectorAll("tr[data-level]");
+	var toggles = document.querySelectorAll(".level-toggle");
+	var search = document.getElementById("search");
+	function apply() {
+		var shown = {};
+		toggles.forEach(function (t) { shown[t.value] = t.checked; });
+		var q = search.value.toLowerCase();
+		rows.forEach(function (row) {
+			var visible = shown[row.dataset.level] &&
+				(q === "" || row.textContent.toLowerCase().indexOf(q) >= 0);
+			row.classList.toggle("hidden", !visible);
+		});
+	}
+	toggles.forEach(function (t) { t.addEventListener("change", apply); });
+	search.addEventListener("input", apply);
+})();
+</script>
+</body>
+</html>
+`))
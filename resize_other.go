@@ -0,0 +1,10 @@
+//go:build !unix
+
+package onylogger
+
+import "os"
+
+// Without SIGWINCH the width is measured again at most once per second.
+const haveResizeSignal = false
+
+func notifyResize(chan<- os.Signal) {}
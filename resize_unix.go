@@ -0,0 +1,15 @@
+//go:build unix
+
+package onylogger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+const haveResizeSignal = true
+
+func notifyResize(c chan<- os.Signal) {
+	signal.Notify(c, syscall.SIGWINCH)
+}
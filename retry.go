@@ -0,0 +1,82 @@
+package onylogger
+
+import (
+	"math/rand"
+	"time"
+)
+
+const (
+	defaultRetryInitial     = 500 * time.Millisecond
+	defaultRetryMax         = 10 * time.Second
+	defaultRetryMaxAttempts = 4
+)
+
+// RetryPolicy decides whether a failed delivery to a remote sink, such as
+// an incident (WithPagerDuty, WithOpsgenie) or an email (WithEmail), is
+// tried again. Deliveries run in the background, so waiting does not hold
+// up logging, but Close waits for them.
+type RetryPolicy interface {
+	// Retry is called after attempt (1 for the first) failed with err. It
+	// returns how long to wait before the next attempt, or false to give
+	// up, in which case err is reported (see SetErrorHandler).
+	Retry(attempt int, err error) (wait time.Duration, ok bool)
+}
+
+// ExponentialBackoff is the default RetryPolicy: it doubles the wait after
+// every attempt, from Initial up to Max, with up to half of it added at
+// random so that many processes failing together do not retry together.
+type ExponentialBackoff struct {
+	Initial time.Duration // defaults to 500ms
+	Max     time.Duration // defaults to 10s
+	// MaxAttempts is the number of attempts, the first included; defaults
+	// to 4. 1 disables retries.
+	MaxAttempts int
+	// GiveUp, when set, is called with the last error of a delivery that
+	// used up its attempts.
+	GiveUp func(err error)
+}
+
+func (b ExponentialBackoff) Retry(attempt int, err error) (time.Duration, bool) {
+	maxAttempts := b.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = defaultRetryMaxAttempts
+	}
+	if attempt >= maxAttempts {
+		if b.GiveUp != nil {
+			b.GiveUp(err)
+		}
+		return 0, false
+	}
+
+	wait, limit := b.Initial, b.Max
+	if wait <= 0 {
+		wait = defaultRetryInitial
+	}
+	if limit <= 0 {
+		limit = defaultRetryMax
+	}
+	for i := 1; i < attempt && wait < limit; i++ {
+		wait *= 2
+	}
+	wait = min(wait, limit)
+	return wait + time.Duration(rand.Int63n(int64(wait)/2+1)), true
+}
+
+// deliver calls send until it succeeds or policy gives up, returning the
+// last error. A nil policy is ExponentialBackoff's defaults.
+func deliver(policy RetryPolicy, send func() error) error {
+	if policy == nil {
+		policy = ExponentialBackoff{}
+	}
+	for attempt := 1; ; attempt++ {
+		err := send()
+		if err == nil {
+			return nil
+		}
+		wait, ok := policy.Retry(attempt, err)
+		if !ok {
+			return err
+		}
+		time.Sleep(wait)
+	}
+}
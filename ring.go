@@ -0,0 +1,60 @@
+package onylogger
+
+import (
+	"sync"
+
+	"github.com/sirupsen/logrus"
+)
+
+// ringBuffer keeps the most recent entries in memory.
+type ringBuffer struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int
+	full    bool
+	total   uint64 // entries seen over the buffer's lifetime
+}
+
+func newRingBuffer(size int) *ringBuffer {
+	return &ringBuffer{entries: make([]Entry, size)}
+}
+
+func (r *ringBuffer) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (r *ringBuffer) Fire(entry *logrus.Entry) error {
+	if demoted(entry, r.Levels()) {
+		return nil
+	}
+	e := newEntry(entry)
+
+	r.mu.Lock()
+	r.entries[r.next] = e
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+	r.total++
+	r.mu.Unlock()
+	return nil
+}
+
+// snapshot returns the buffered entries, oldest first.
+func (r *ringBuffer) snapshot() []Entry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Entry(nil), r.entries[:r.next]...)
+	}
+	return append(append([]Entry(nil), r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// seen returns how many entries the buffer has seen, to tell whether a
+// snapshot is out of date.
+func (r *ringBuffer) seen() uint64 {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.total
+}
@@ -0,0 +1,302 @@
+package onylogger
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const rotatedSuffixLayout = "20060102-150405"
+
+// FileRotate starts a new file once the current one would grow beyond
+// maxSize bytes. The full file is renamed to "<path>.<timestamp>" and at
+// most maxFiles rotated files are kept (0 keeps all of them).
+func FileRotate(maxSize int64, maxFiles int) FileOption {
+	return func(fc *fileConfig) {
+		fc.maxSize = maxSize
+		fc.maxFiles = maxFiles
+	}
+}
+
+// FileCompress gzips rotated files in the background.
+func FileCompress() FileOption {
+	return func(fc *fileConfig) {
+		fc.compress = true
+	}
+}
+
+// FileMaxTotalSize deletes the oldest rotated files while all of them
+// together take up more than maxBytes of disk space. It can be combined
+// with the file count limit of FileRotate.
+func FileMaxTotalSize(maxBytes int64) FileOption {
+	return func(fc *fileConfig) {
+		fc.maxTotalSize = maxBytes
+	}
+}
+
+// logFile is the file behind a file sink. It tracks its size to rotate on
+// time and does compression and retention of rotated files in the
+// background.
+type logFile struct {
+	cfg    *fileConfig
+	report func(error) // for failures that do not fail a write
+
+	mu   sync.Mutex
+	f    *os.File
+	size int64
+
+	cleanupMu sync.Mutex // one compression/retention pass at a time
+	pending   sync.WaitGroup
+
+	busyMu sync.Mutex
+	busy   map[string]bool // rotated files still being compressed
+}
+
+func openLogFile(cfg *fileConfig, report func(error)) (*logFile, error) {
+	lf := &logFile{cfg: cfg, report: report}
+	if err := lf.open(); err != nil {
+		return nil, err
+	}
+	return lf, nil
+}
+
+// open (re)opens the file at the configured path. Must be called with mu
+// held, or before the file is shared.
+func (lf *logFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(lf.cfg.path), lf.cfg.dirMode); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(lf.cfg.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, lf.cfg.fileMode)
+	if err != nil {
+		return err
+	}
+	if lf.cfg.uid != -1 || lf.cfg.gid != -1 {
+		if err := f.Chown(lf.cfg.uid, lf.cfg.gid); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	lf.f, lf.size = f, fi.Size()
+	return nil
+}
+
+func (lf *logFile) Write(p []byte) (int, error) {
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+
+	if lf.cfg.shared {
+		if err := lf.syncShared(); err != nil {
+			return 0, err
+		}
+		if lf.cfg.lock {
+			defer func() { unlockFile(lf.f) }()
+		}
+	}
+
+	if lf.cfg.maxSize > 0 && lf.size > 0 && lf.size+int64(len(p)) > lf.cfg.maxSize {
+		if err := lf.rotate(); err != nil {
+			// Keep writing to the old file rather than losing entries.
+			lf.report(fmt.Errorf("failed to rotate log file %s: %w", lf.cfg.path, err))
+		} else if lf.cfg.shared {
+			if err := lf.syncShared(); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	n, err := lf.f.Write(p)
+	lf.size += int64(n)
+	return n, err
+}
+
+// rotate moves the current file aside and starts a new one. Must be called
+// with mu held.
+func (lf *logFile) rotate() error {
+	rotated := lf.cfg.path + "." + time.Now().Format(rotatedSuffixLayout)
+	for i := 1; fileExists(rotated) || fileExists(rotated+".gz"); i++ {
+		rotated = fmt.Sprintf("%s.%s.%d", lf.cfg.path, time.Now().Format(rotatedSuffixLayout), i)
+	}
+
+	if lf.cfg.shared {
+		// Rename while the old file is still open so its lock keeps other
+		// processes from rotating at the same time.
+		if err := os.Rename(lf.cfg.path, rotated); err != nil {
+			return err
+		}
+		lf.f.Close()
+	} else {
+		if err := lf.f.Close(); err != nil {
+			return err
+		}
+		if err := os.Rename(lf.cfg.path, rotated); err != nil {
+			lf.open()
+			return err
+		}
+	}
+	if err := lf.open(); err != nil {
+		return err
+	}
+
+	lf.pending.Add(1)
+	lf.busyMu.Lock()
+	if lf.busy == nil {
+		lf.busy = make(map[string]bool)
+	}
+	lf.busy[absPath(rotated)] = true
+	lf.busyMu.Unlock()
+	go lf.cleanup(rotated)
+	return nil
+}
+
+func fileExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
+// cleanup compresses a freshly rotated file and applies retention.
+func (lf *logFile) cleanup(rotated string) {
+	defer lf.pending.Done()
+	defer func() {
+		lf.busyMu.Lock()
+		delete(lf.busy, absPath(rotated))
+		lf.busyMu.Unlock()
+	}()
+
+	lf.cleanupMu.Lock()
+	defer lf.cleanupMu.Unlock()
+
+	if lf.cfg.compress {
+		if err := gzipInPlace(rotated, lf.cfg); err != nil {
+			lf.report(fmt.Errorf("failed to compress %s: %w", rotated, err))
+		}
+	}
+	if err := lf.applyRetention(); err != nil {
+		lf.report(fmt.Errorf("failed to prune rotated logs of %s: %w", lf.cfg.path, err))
+	}
+}
+
+// owns reports whether path is a rotated file, or the compressed copy of
+// one, that is not done being compressed.
+func (lf *logFile) owns(path string) bool {
+	path = absPath(path)
+	lf.busyMu.Lock()
+	defer lf.busyMu.Unlock()
+	return lf.busy[path] || lf.busy[strings.TrimSuffix(path, ".gz")]
+}
+
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// gzipInPlace replaces path with path.gz, created with the permissions and
+// owner configured for the log file.
+func gzipInPlace(path string, cfg *fileConfig) error {
+	in, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(path+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, cfg.fileMode)
+	if err != nil {
+		return err
+	}
+	if cfg.uid != -1 || cfg.gid != -1 {
+		out.Chown(cfg.uid, cfg.gid)
+	}
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		out.Close()
+		os.Remove(path + ".gz")
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(path + ".gz")
+		return err
+	}
+	return os.Remove(path)
+}
+
+type rotatedFile struct {
+	path string
+	info os.FileInfo
+}
+
+// rotatedFiles lists the rotated siblings of the active file, oldest first.
+func (lf *logFile) rotatedFiles() ([]rotatedFile, error) {
+	matches, err := filepath.Glob(globEscape(lf.cfg.path) + ".*")
+	if err != nil {
+		return nil, err
+	}
+
+	var rotated []rotatedFile
+	for _, path := range matches {
+		if fi, err := os.Stat(path); err == nil {
+			rotated = append(rotated, rotatedFile{path: path, info: fi})
+		}
+	}
+	sort.Slice(rotated, func(i, j int) bool {
+		return rotated[i].info.ModTime().Before(rotated[j].info.ModTime())
+	})
+	return rotated, nil
+}
+
+func (lf *logFile) applyRetention() error {
+	if lf.cfg.maxFiles <= 0 && lf.cfg.maxTotalSize <= 0 {
+		return nil
+	}
+	rotated, err := lf.rotatedFiles()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, r := range rotated {
+		total += r.info.Size()
+	}
+
+	for i, r := range rotated {
+		overCount := lf.cfg.maxFiles > 0 && len(rotated)-i > lf.cfg.maxFiles
+		overSize := lf.cfg.maxTotalSize > 0 && total > lf.cfg.maxTotalSize
+		if !overCount && !overSize {
+			break
+		}
+		if err := os.Remove(r.path); err != nil {
+			return err
+		}
+		total -= r.info.Size()
+	}
+	return nil
+}
+
+func globEscape(path string) string {
+	return strings.NewReplacer(`*`, `\*`, `?`, `\?`, `[`, `\[`).Replace(path)
+}
+
+// Close closes the file after background compression has finished.
+func (lf *logFile) Close() error {
+	lf.pending.Wait()
+
+	lf.mu.Lock()
+	defer lf.mu.Unlock()
+	return lf.f.Close()
+}
@@ -0,0 +1,90 @@
+package onylogger
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// safeFormat formats e with f, surviving field values whose String,
+// MarshalJSON or similar methods panic: the entry is formatted again with
+// each such field replaced by a note, so the log call still writes a line
+// instead of taking the process down.
+func safeFormat(f entryFormatter, e *Entry, b *bytes.Buffer) ([]byte, error) {
+	start := 0
+	if b != nil {
+		start = b.Len()
+	}
+	out, err := tryFormat(f, e, b)
+	if _, panicked := err.(formatPanic); !panicked {
+		return out, err
+	}
+
+	// Find the fields at fault by formatting each on its own.
+	fixed := *e
+	fixed.Fields = make(Fields, len(e.Fields))
+	for k, v := range e.Fields {
+		probe := Entry{Time: e.Time, Level: e.Level, Fields: Fields{k: v}}
+		if _, err := tryFormat(f, &probe, nil); err != nil {
+			if p, panicked := err.(formatPanic); panicked {
+				v = fmt.Sprintf("<PANIC=%v>", p.value)
+			}
+		}
+		fixed.Fields[k] = v
+	}
+	if b != nil {
+		b.Truncate(start)
+	}
+	out, err = tryFormat(f, &fixed, b)
+	if _, panicked := err.(formatPanic); panicked && b != nil {
+		b.Truncate(start)
+	}
+	return out, err
+}
+
+// marshalFields is json.Marshal of fields, with the values whose encoding
+// panicked replaced by "<PANIC=...>" and the panic reported.
+func marshalFields(fields Fields, report func(error)) ([]byte, error) {
+	out, err := tryMarshal(fields)
+	if _, panicked := err.(formatPanic); !panicked {
+		return out, err
+	}
+	fixed := make(Fields, len(fields))
+	for k, v := range fields {
+		if _, err := tryMarshal(v); err != nil {
+			if p, panicked := err.(formatPanic); panicked {
+				report(fmt.Errorf("field %s panicked while encoding: %v", k, p.value))
+				v = fmt.Sprintf("<PANIC=%v>", p.value)
+			}
+		}
+		fixed[k] = v
+	}
+	return tryMarshal(fixed)
+}
+
+func tryMarshal(v interface{}) (out []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = nil, formatPanic{r}
+		}
+	}()
+	return json.Marshal(v)
+}
+
+// formatPanic is the error tryFormat returns when the formatter panicked.
+type formatPanic struct {
+	value interface{}
+}
+
+func (p formatPanic) Error() string {
+	return fmt.Sprintf("onylogger: formatting panicked: %v", p.value)
+}
+
+func tryFormat(f entryFormatter, e *Entry, b *bytes.Buffer) (out []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			out, err = nil, formatPanic{r}
+		}
+	}()
+	return f.format(e, b)
+}
@@ -0,0 +1,119 @@
+package onylogger
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+const defaultSamplingKeep = 0.1
+
+// SamplingConfig tunes WithAdaptiveSampling.
+type SamplingConfig struct {
+	// Threshold is the number of entries per second past which Info and
+	// less severe entries are sampled.
+	Threshold int
+	// Keep is the fraction of those entries kept while sampling; defaults
+	// to 0.1.
+	Keep float64
+}
+
+// SamplingStats reports what adaptive sampling did so far.
+type SamplingStats struct {
+	Active  bool   // sampling right now
+	Kept    uint64 // entries kept while sampling
+	Dropped uint64 // entries sampled away
+}
+
+// WithAdaptiveSampling keeps up under sustained load: while more than
+// cfg.Threshold entries per second are logged, only a random cfg.Keep of
+// the Info, Debug and Trace entries are; Warn and more severe ones always
+// are. Full logging comes back once the volume drops under the threshold.
+// A Warn marks the start of sampling and an Info its end, with how many
+// entries were sampled away.
+//
+//	onylogger.WithAdaptiveSampling(onylogger.SamplingConfig{Threshold: 5000})
+func WithAdaptiveSampling(cfg SamplingConfig) Option {
+	return func(o *options) {
+		if cfg.Keep <= 0 || cfg.Keep > 1 {
+			cfg.Keep = defaultSamplingKeep
+		}
+		o.sampling = &cfg
+	}
+}
+
+// Sampling reports the counters of adaptive sampling; they are zero unless
+// the logger was created WithAdaptiveSampling.
+func (l *OnyLogger) Sampling() SamplingStats {
+	s := l.sampler
+	if s == nil {
+		return SamplingStats{}
+	}
+	s.mu.Lock()
+	active := s.active
+	s.mu.Unlock()
+	return SamplingStats{Active: active, Kept: s.kept.Load(), Dropped: s.dropped.Load()}
+}
+
+// sampler measures the rate of entries and samples those of Info level and
+// below while it is over the threshold. It counts every entry, sampled away
+// or not, so that the rate is that of what the program logs.
+type sampler struct {
+	cfg    SamplingConfig
+	notify func(level Level, fields Fields, message string)
+
+	mu      sync.Mutex
+	window  time.Time // start of the second being counted
+	count   int
+	active  bool
+	skipped uint64 // sampled away since sampling started
+
+	kept    atomic.Uint64
+	dropped atomic.Uint64
+}
+
+func newSampler(cfg SamplingConfig, notify func(Level, Fields, string)) *sampler {
+	return &sampler{cfg: cfg, notify: notify, window: time.Now()}
+}
+
+func (s *sampler) process(e Entry) (Entry, bool) {
+	now := time.Now()
+
+	s.mu.Lock()
+	var started, stopped bool
+	var skipped uint64
+	if elapsed := now.Sub(s.window); elapsed >= time.Second {
+		if s.active && float64(s.count)/elapsed.Seconds() <= float64(s.cfg.Threshold) {
+			s.active, stopped, skipped = false, true, s.skipped
+		}
+		s.window, s.count = now, 0
+	}
+	s.count++
+	if !s.active && s.count > s.cfg.Threshold {
+		s.active, started, s.skipped = true, true, 0
+	}
+	sampling := s.active && e.Level >= InfoLevel
+	drop := sampling && rand.Float64() >= s.cfg.Keep
+	if drop {
+		s.skipped++
+	}
+	s.mu.Unlock()
+
+	if stopped {
+		s.notify(InfoLevel, Fields{"sampled_away": skipped}, fmt.Sprintf("Stopped sampling, %d entries were sampled away", skipped))
+	}
+	if started {
+		s.notify(WarnLevel, nil, fmt.Sprintf("Over %d entries per second, sampling Info and less severe entries", s.cfg.Threshold))
+	}
+
+	switch {
+	case !sampling:
+	case drop:
+		s.dropped.Add(1)
+	default:
+		s.kept.Add(1)
+	}
+	return e, !drop
+}
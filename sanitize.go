@@ -0,0 +1,98 @@
+package onylogger
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// WithRawControlChars turns off the escaping of control characters in text
+// output. By default, ESC and the other control characters in messages,
+// field keys and field values are written as Go escapes, e.g. \x1b, so that
+// logging untrusted input such as HTTP headers cannot move the cursor,
+// recolor or retitle the terminal. Newlines and tabs are kept.
+func WithRawControlChars() Option {
+	return func(o *options) {
+		o.rawControl = true
+	}
+}
+
+// sanitize escapes the control characters of s other than newline and
+// tab, and bytes that are not valid UTF-8, which some terminals read as C1
+// controls.
+func sanitize(s string) string {
+	i := 0
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		if isUnsafe(r, size) {
+			break
+		}
+		i += size
+	}
+	if i == len(s) {
+		return s
+	}
+
+	var b strings.Builder
+	b.Grow(len(s) + 8)
+	b.WriteString(s[:i])
+	for i < len(s) {
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case !isUnsafe(r, size):
+			b.WriteString(s[i : i+size])
+		case r == utf8.RuneError:
+			b.WriteString(`\x`)
+			b.WriteString(strconv.FormatUint(uint64(s[i])|0x100, 16)[1:])
+		default:
+			b.WriteString(strings.Trim(strconv.QuoteRune(r), "'"))
+		}
+		i += size
+	}
+	return b.String()
+}
+
+func isUnsafe(r rune, size int) bool {
+	switch {
+	case r == '\n', r == '\t':
+		return false
+	case r < 0x20, r == 0x7f, r >= 0x80 && r <= 0x9f:
+		return true
+	}
+	return r == utf8.RuneError && size == 1
+}
+
+// text returns s as f writes it, escaped unless WithRawControlChars.
+func (f *emojiFormatter) text(s string) string {
+	if f.rawControl {
+		return s
+	}
+	return sanitize(s)
+}
+
+// sanitizeLinks is sanitize for messages written with their hyperlinks,
+// keeping the sequences Link made.
+func sanitizeLinks(s string) string {
+	if !strings.Contains(s, osc8Start) {
+		return sanitize(s)
+	}
+
+	var b strings.Builder
+	for {
+		start := strings.Index(s, osc8Start)
+		if start < 0 {
+			break
+		}
+		rest := s[start+len(osc8Start):]
+		url, rest, ok1 := strings.Cut(rest, osc8End)
+		text, rest, ok2 := strings.Cut(rest, osc8Start+osc8End)
+		if !ok1 || !ok2 {
+			break
+		}
+		b.WriteString(sanitize(s[:start]))
+		b.WriteString(osc8Start + sanitize(url) + osc8End + sanitize(text) + osc8Start + osc8End)
+		s = rest
+	}
+	b.WriteString(sanitize(s))
+	return b.String()
+}
@@ -0,0 +1,48 @@
+package onylogger
+
+import "github.com/sirupsen/logrus"
+
+// WithServiceInfo adds service, env, hostname and version fields to every
+// entry, for log ingestion. The version comes from the binary's build
+// info. The fields show up in JSON outputs; text outputs leave them out.
+func WithServiceInfo(name, env string) Option {
+	return func(o *options) {
+		o.service = &serviceInfo{name: name, env: env}
+	}
+}
+
+type serviceInfo struct {
+	name, env string
+}
+
+// serviceHook adds the WithServiceInfo, WithKubernetesInfo or WithCloudInfo
+// fields. Fields set on the entry itself take precedence.
+type serviceHook struct {
+	fields logrus.Fields
+}
+
+func newServiceHook(si *serviceInfo) serviceHook {
+	info := readBuildInfo(StartupOptions{Name: si.name})
+	fields := logrus.Fields{
+		"service":  info.name,
+		"hostname": info.hostname,
+		"version":  info.version,
+	}
+	if si.env != "" {
+		fields["env"] = si.env
+	}
+	return serviceHook{fields: fields}
+}
+
+func (h serviceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h serviceHook) Fire(entry *logrus.Entry) error {
+	for k, v := range h.fields {
+		if _, ok := entry.Data[k]; !ok {
+			entry.Data[k] = v
+		}
+	}
+	return nil
+}
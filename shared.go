@@ -0,0 +1,48 @@
+package onylogger
+
+import "os"
+
+// FileShared lets several processes append to the same file. Every entry
+// (or buffered batch of whole entries) reaches the file in a single
+// O_APPEND write, and a file rotated or renamed by another process is
+// reopened before the next write. With lock set, writes also take an
+// advisory lock on the file, which keeps rotation consistent between
+// processes and protects large entries on file systems where appends are
+// not atomic.
+func FileShared(lock bool) FileOption {
+	return func(fc *fileConfig) {
+		fc.shared = true
+		fc.lock = lock
+	}
+}
+
+// syncShared prepares a write to a shared file: it takes the lock if one is
+// configured, follows the path to a new file if another process moved the
+// old one away, and picks up the size others have written. Must be called
+// with mu held; the caller releases the lock with unlockFile.
+func (lf *logFile) syncShared() error {
+	for {
+		if lf.cfg.lock {
+			if err := lockFile(lf.f); err != nil {
+				return err
+			}
+		}
+
+		cur, err := lf.f.Stat()
+		if err != nil {
+			return err
+		}
+		if onDisk, err := os.Stat(lf.cfg.path); err == nil && os.SameFile(cur, onDisk) {
+			lf.size = cur.Size()
+			return nil
+		}
+
+		if lf.cfg.lock {
+			unlockFile(lf.f)
+		}
+		lf.f.Close()
+		if err := lf.open(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,79 @@
+package onylogger
+
+import (
+	"fmt"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// sink is an additional destination with its own formatter, fed from a
+// logrus hook. Hooks fire concurrently, so writes are serialized here.
+type sink struct {
+	name      string
+	formatter entryFormatter
+	out       io.Writer
+	report    func(error)
+
+	// flushOn, when set, reports entries that must reach their destination
+	// right away instead of waiting in a buffer.
+	flushOn func(*logrus.Entry) bool
+	flush   func() error
+
+	mu     sync.Mutex
+	closed atomic.Bool
+}
+
+// addSink feeds entries rendered by formatter to out, through the write
+// path of queue, with escape sequences stripped. Errors are reported with
+// name (see SetErrorHandler). Closing out itself is left to the caller.
+func (l *OnyLogger) addSink(name string, out io.Writer, formatter entryFormatter, queue *QueueConfig) *sink {
+	// Fluent frames are MessagePack, where an escape byte is data.
+	if _, binary := formatter.(*fluentFormatter); !binary {
+		out = StripANSIWriter(out)
+	}
+	out = reportingWriter{out: out, report: l.reportError, what: name}
+	s := &sink{name: name, formatter: formatter, out: l.wrap(out, queue), report: l.reportError}
+	l.closers = append(l.closers, s)
+	l.AddHook(s)
+	return s
+}
+
+func (s *sink) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *sink) Fire(entry *logrus.Entry) error {
+	if s.closed.Load() || demoted(entry, s.Levels()) {
+		return nil
+	}
+
+	e := entryFrom(entry)
+	serialized, err := safeFormat(s.formatter, &e, nil)
+	if err != nil {
+		s.report(fmt.Errorf("failed to format an entry for %s: %w", s.name, err))
+		return nil
+	}
+
+	// Write errors are reported by the writer.
+	s.mu.Lock()
+	s.out.Write(serialized)
+	s.mu.Unlock()
+
+	// Flush outside the lock, so other entries are not held up meanwhile.
+	if s.flushOn != nil && s.flushOn(entry) {
+		if err := s.flush(); err != nil {
+			s.report(fmt.Errorf("failed to flush %s: %w", s.name, err))
+		}
+	}
+	return nil
+}
+
+// Close stops the sink from accepting entries; the writers behind it are
+// closed separately so they can drain first.
+func (s *sink) Close() error {
+	s.closed.Store(true)
+	return nil
+}
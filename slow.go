@@ -0,0 +1,71 @@
+package onylogger
+
+import (
+	"bytes"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithSlowStacks makes WarnIfSlow include in its warning the stack of the
+// slow operation, taken when it went over its threshold.
+func WithSlowStacks() Option {
+	return func(o *options) {
+		o.slowStacks = true
+	}
+}
+
+// WarnIfSlow runs fn and logs a warning if it took longer than threshold,
+// with the duration in a "duration" field. It returns how long fn took.
+//
+//	log.WarnIfSlow(200*time.Millisecond, "cache refresh", refresh)
+func (l *OnyLogger) WarnIfSlow(threshold time.Duration, name string, fn func()) time.Duration {
+	var (
+		mu    sync.Mutex
+		stack []byte
+	)
+	if l.opts.slowStacks && l.enabled(WarnLevel) {
+		id := goroutineID()
+		timer := time.AfterFunc(threshold, func() {
+			s := goroutineStack(id)
+			mu.Lock()
+			stack = s
+			mu.Unlock()
+		})
+		defer timer.Stop()
+	}
+
+	start := time.Now()
+	fn()
+	took := time.Since(start)
+	if took <= threshold {
+		return took
+	}
+
+	fields := logrus.Fields{
+		"emoji":     "[🐢] ",
+		"duration":  took,
+		"threshold": threshold,
+	}
+	mu.Lock()
+	if stack != nil {
+		fields["stack"] = string(stack)
+	}
+	mu.Unlock()
+	l.emitf(WarnLevel, fields, "Slow %s: took %s", name, humanizeDuration(took))
+	return took
+}
+
+// goroutineStack returns the stack of the goroutine with the given ID, or
+// nil if it is gone.
+func goroutineStack(id uint64) []byte {
+	header := []byte("goroutine " + strconv.FormatUint(id, 10) + " [")
+	for _, s := range bytes.Split(allStacks(), []byte("\n\n")) {
+		if bytes.HasPrefix(s, header) {
+			return bytes.TrimSpace(s)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,145 @@
+package onylogger
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/binary"
+	"net"
+	"sync"
+	"time"
+)
+
+// Framing selects how entries are delimited on a socket.
+type Framing int
+
+const (
+	// FrameNewline terminates every entry with "\n".
+	FrameNewline Framing = iota
+	// FrameLengthPrefixed precedes every entry with its length as a 4-byte
+	// big-endian integer.
+	FrameLengthPrefixed
+)
+
+// SocketOption configures a socket sink added with WithSocket.
+type SocketOption func(*socketConfig)
+
+type socketConfig struct {
+	network, addr string
+	framing       Framing
+	format        Format
+	fields        FieldFilter
+	retryInterval time.Duration
+	queue         *QueueConfig
+	tlsConfig     *tls.Config
+}
+
+// SocketTLS connects a "tcp" socket over TLS, e.g. to syslog over TLS on
+// port 6514; see NewTLSConfig for client certificates and CA pools.
+func SocketTLS(cfg *tls.Config) SocketOption {
+	return func(sc *socketConfig) {
+		sc.tlsConfig = cfg
+	}
+}
+
+// SocketQueue sets how entries are queued for the socket, overriding
+// WithAsync.
+func SocketQueue(q QueueConfig) SocketOption {
+	return func(sc *socketConfig) {
+		sc.queue = &q
+	}
+}
+
+// SocketFraming sets how entries are delimited. Defaults to FrameNewline.
+func SocketFraming(framing Framing) SocketOption {
+	return func(sc *socketConfig) {
+		sc.framing = framing
+	}
+}
+
+// SocketFormat sets the encoding of a socket sink. Defaults to FormatJSON.
+func SocketFormat(format Format) SocketOption {
+	return func(sc *socketConfig) {
+		sc.format = format
+	}
+}
+
+// WithSocket writes every entry to a "unix", "unixgram", "tcp" or "udp"
+// socket, e.g. a local log shipper. Broken connections are redialed on
+// the next entry, at most once per second.
+func WithSocket(network, addr string, opts ...SocketOption) Option {
+	return func(o *options) {
+		sc := &socketConfig{
+			network:       network,
+			addr:          addr,
+			format:        FormatJSON,
+			retryInterval: time.Second,
+		}
+		for _, opt := range opts {
+			opt(sc)
+		}
+		o.sockets = append(o.sockets, sc)
+	}
+}
+
+func (l *OnyLogger) addSocket(sc *socketConfig, o *options) {
+	w := &socketWriter{cfg: sc}
+	l.closers = append(l.closers, w)
+	l.addSink(sc.network+" socket "+sc.addr, w, o.newFormatter(sc.format, StyleEmoji, sc.fields, false), o.queue(sc.queue))
+}
+
+type socketWriter struct {
+	cfg *socketConfig
+
+	mu      sync.Mutex
+	conn    net.Conn
+	retryAt time.Time
+}
+
+func (w *socketWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		if time.Now().Before(w.retryAt) {
+			return 0, net.ErrClosed
+		}
+		conn, err := dial(w.cfg.network, w.cfg.addr, 10*time.Second, w.cfg.tlsConfig)
+		if err != nil {
+			w.retryAt = time.Now().Add(w.cfg.retryInterval)
+			return 0, err
+		}
+		w.conn = conn
+	}
+
+	// One Write per entry so datagram sockets get one entry per packet.
+	w.conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
+	if _, err := w.conn.Write(w.frame(p)); err != nil {
+		w.conn.Close()
+		w.conn = nil
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w *socketWriter) frame(p []byte) []byte {
+	if w.cfg.framing == FrameLengthPrefixed {
+		p = bytes.TrimRight(p, "\n")
+		return append(binary.BigEndian.AppendUint32(nil, uint32(len(p))), p...)
+	}
+	if len(p) == 0 || p[len(p)-1] != '\n' {
+		return append(p, '\n')
+	}
+	return p
+}
+
+func (w *socketWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.conn == nil {
+		return nil
+	}
+	err := w.conn.Close()
+	w.conn = nil
+	return err
+}
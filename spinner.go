@@ -0,0 +1,94 @@
+package onylogger
+
+import (
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+var spinnerFrames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const spinnerInterval = 100 * time.Millisecond
+
+// Spinner is an animated single-line indicator for work in progress. Regular
+// log entries written while it spins are printed above it.
+type Spinner struct {
+	l *OnyLogger
+
+	mu      sync.Mutex
+	message string
+	frame   int
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// Spinner starts a spinner showing the given message until Stop, Success or
+// Fail is called.
+func (l *OnyLogger) Spinner(message string) *Spinner {
+	s := &Spinner{
+		l:       l,
+		message: message,
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	l.render.add(s)
+	go s.run()
+	return s
+}
+
+func (s *Spinner) run() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(spinnerInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			s.frame = (s.frame + 1) % len(spinnerFrames)
+			s.mu.Unlock()
+			s.l.render.refresh()
+		}
+	}
+}
+
+func (s *Spinner) render() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return colorMagenta + "[" + spinnerFrames[s.frame] + "]" + colorReset + " " + s.message
+}
+
+// Update replaces the message shown next to the spinner.
+func (s *Spinner) Update(message string) {
+	s.mu.Lock()
+	s.message = message
+	s.mu.Unlock()
+	s.l.render.refresh()
+}
+
+// Stop removes the spinner without logging anything.
+func (s *Spinner) Stop() {
+	s.once.Do(func() {
+		close(s.stop)
+		<-s.done
+		s.l.render.remove(s)
+	})
+}
+
+// Success stops the spinner and logs the message with the "✅" emoji.
+func (s *Spinner) Success(message string) {
+	s.Stop()
+	s.l.logEmoji(logrus.InfoLevel, "[✅] ", message)
+}
+
+// Fail stops the spinner and logs the message at Error level.
+func (s *Spinner) Fail(message string) {
+	s.Stop()
+	s.l.emit(ErrorLevel, nil, message)
+}
@@ -0,0 +1,176 @@
+package onylogger
+
+import (
+	"database/sql"
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+const sqliteTimeLayout = "2006-01-02T15:04:05.000000Z"
+
+var sqlIdentifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// SQLiteConfig describes the SQLite sink added with WithSQLite.
+type SQLiteConfig struct {
+	// DB is an open SQLite database. The package does not import a driver,
+	// so open it with the one you already use (mattn/go-sqlite3,
+	// modernc.org/sqlite, ...).
+	DB *sql.DB
+	// Table defaults to "logs".
+	Table string
+
+	// Retention deletes entries older than this. MaxRows keeps at most this
+	// many of the newest entries. Zero disables either rule.
+	Retention time.Duration
+	MaxRows   int
+	// PruneInterval is how often the retention rules run; defaults to a
+	// minute.
+	PruneInterval time.Duration
+}
+
+// WithSQLite stores every entry as a row (time, level, message, and fields
+// as JSON) in a SQLite table, switching the database to WAL mode so it can
+// be queried while the program runs:
+//
+//	SELECT time, message FROM logs
+//	WHERE level = 'error' AND json_extract(fields, '$.component') = 'db';
+func WithSQLite(cfg SQLiteConfig) Option {
+	return func(o *options) {
+		o.sqlite = append(o.sqlite, cfg)
+	}
+}
+
+type sqliteHook struct {
+	cfg    SQLiteConfig
+	insert *sql.Stmt
+	report func(error)
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+func (l *OnyLogger) addSQLite(cfg SQLiteConfig) {
+	if cfg.Table == "" {
+		cfg.Table = "logs"
+	}
+	if cfg.PruneInterval == 0 {
+		cfg.PruneInterval = time.Minute
+	}
+
+	h, err := newSQLiteHook(cfg, l.reportError)
+	if err != nil {
+		l.emitf(ErrorLevel, nil, "Failed to set up SQLite sink: %v", err)
+		return
+	}
+	l.closers = append(l.closers, h)
+	l.AddHook(h)
+}
+
+func newSQLiteHook(cfg SQLiteConfig, report func(error)) (*sqliteHook, error) {
+	if cfg.DB == nil {
+		return nil, fmt.Errorf("no database given")
+	}
+	if !sqlIdentifier.MatchString(cfg.Table) {
+		return nil, fmt.Errorf("invalid table name %q", cfg.Table)
+	}
+
+	for _, stmt := range []string{
+		`PRAGMA journal_mode=WAL`,
+		`CREATE TABLE IF NOT EXISTS ` + cfg.Table + ` (
+			id      INTEGER PRIMARY KEY AUTOINCREMENT,
+			time    TEXT NOT NULL,
+			level   TEXT NOT NULL,
+			message TEXT NOT NULL,
+			fields  TEXT NOT NULL DEFAULT '{}'
+		)`,
+		`CREATE INDEX IF NOT EXISTS ` + cfg.Table + `_time ON ` + cfg.Table + ` (time)`,
+		`CREATE INDEX IF NOT EXISTS ` + cfg.Table + `_level ON ` + cfg.Table + ` (level)`,
+	} {
+		if _, err := cfg.DB.Exec(stmt); err != nil {
+			return nil, err
+		}
+	}
+
+	insert, err := cfg.DB.Prepare(`INSERT INTO ` + cfg.Table + ` (time, level, message, fields) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return nil, err
+	}
+
+	h := &sqliteHook{cfg: cfg, insert: insert, report: report, stop: make(chan struct{}), done: make(chan struct{})}
+	if cfg.Retention > 0 || cfg.MaxRows > 0 {
+		go h.pruneLoop()
+	} else {
+		close(h.done)
+	}
+	return h, nil
+}
+
+func (h *sqliteHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h *sqliteHook) Fire(entry *logrus.Entry) error {
+	if demoted(entry, h.Levels()) {
+		return nil
+	}
+	e := newEntry(entry)
+	for k, v := range e.Fields {
+		if err, ok := v.(error); ok {
+			e.Fields[k] = err.Error()
+		}
+	}
+	fields, err := marshalFields(e.Fields, h.report)
+	if err == nil {
+		_, err = h.insert.Exec(e.Time.UTC().Format(sqliteTimeLayout), e.Level.String(), e.Message, string(fields))
+	}
+	if err != nil {
+		h.report(fmt.Errorf("failed to write to the SQLite log table: %w", err))
+	}
+	return nil
+}
+
+func (h *sqliteHook) pruneLoop() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(h.cfg.PruneInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := h.prune(); err != nil {
+			h.report(fmt.Errorf("failed to prune the SQLite log table: %w", err))
+		}
+		select {
+		case <-h.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (h *sqliteHook) prune() error {
+	if h.cfg.Retention > 0 {
+		cutoff := time.Now().Add(-h.cfg.Retention).UTC().Format(sqliteTimeLayout)
+		if _, err := h.cfg.DB.Exec(`DELETE FROM `+h.cfg.Table+` WHERE time < ?`, cutoff); err != nil {
+			return err
+		}
+	}
+	if h.cfg.MaxRows > 0 {
+		_, err := h.cfg.DB.Exec(`DELETE FROM `+h.cfg.Table+` WHERE id <= (SELECT MAX(id) FROM `+h.cfg.Table+`) - ?`, h.cfg.MaxRows)
+		return err
+	}
+	return nil
+}
+
+// Close stops pruning. The database stays open; it belongs to the caller.
+func (h *sqliteHook) Close() error {
+	select {
+	case <-h.stop:
+	default:
+		close(h.stop)
+	}
+	<-h.done
+	return h.insert.Close()
+}
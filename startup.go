@@ -0,0 +1,95 @@
+package onylogger
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// StartupOptions customizes the banner printed by StartupInfo.
+type StartupOptions struct {
+	// Name is shown in the banner title; defaults to the executable name.
+	Name string
+	// Version overrides the module version from the build info, which is
+	// "(devel)" for binaries built from a checkout.
+	Version string
+}
+
+// buildInfo is what StartupInfo reports about the running binary.
+type buildInfo struct {
+	name, version, commit, goVersion, hostname string
+	pid                                        int
+}
+
+func readBuildInfo(opts StartupOptions) buildInfo {
+	info := buildInfo{
+		name:      opts.Name,
+		version:   opts.Version,
+		goVersion: runtime.Version(),
+		pid:       os.Getpid(),
+	}
+	if info.name == "" {
+		info.name = filepath.Base(os.Args[0])
+	}
+	info.hostname, _ = os.Hostname()
+
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		if info.version == "" {
+			info.version = bi.Main.Version
+		}
+		var modified bool
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.commit = s.Value
+			case "vcs.modified":
+				modified = s.Value == "true"
+			}
+		}
+		if len(info.commit) > 12 {
+			info.commit = info.commit[:12]
+		}
+		if modified && info.commit != "" {
+			info.commit += " (dirty)"
+		}
+	}
+	if info.version == "" {
+		info.version = "(devel)"
+	}
+	if info.commit == "" {
+		info.commit = "unknown"
+	}
+	return info
+}
+
+// StartupInfo logs a banner with the program's version, git commit, Go
+// version, PID and hostname. Call it once at program start.
+func (l *OnyLogger) StartupInfo(opts StartupOptions) {
+	if !l.enabled(InfoLevel) {
+		return
+	}
+	info := readBuildInfo(opts)
+
+	var banner strings.Builder
+	fmt.Fprintf(&banner, "%s %s", info.name, info.version)
+	for _, row := range [][2]string{
+		{"commit", info.commit},
+		{"go", info.goVersion},
+		{"pid", fmt.Sprint(info.pid)},
+		{"host", info.hostname},
+	} {
+		fmt.Fprintf(&banner, "\n    %-8s %s", row[0], row[1])
+	}
+
+	l.emit(InfoLevel, Fields{
+		"emoji":      "[🚀] ",
+		"version":    info.version,
+		"commit":     info.commit,
+		"go_version": info.goVersion,
+		"pid":        info.pid,
+		"hostname":   info.hostname,
+	}, banner.String())
+}
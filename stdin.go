@@ -0,0 +1,125 @@
+package onylogger
+
+import (
+	"context"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"golang.org/x/term"
+)
+
+// Prompts read stdin through one goroutine that reads when asked to and
+// hands out what it read. Nothing read ahead gets lost between prompts,
+// e.g. answers that are piped in, and line and raw (keystroke) reads can
+// take turns. A prompt that stops waiting interrupts its read (see
+// waitStdin), so between prompts nothing reads stdin and child processes
+// like editors get it; where reads cannot be interrupted, the read is left
+// in flight and the next prompt gets what it reads.
+var (
+	stdinOnce   sync.Once
+	stdinWant   = make(chan struct{}, 1)
+	stdinChunks = make(chan []byte)
+	stdinErr    error // set before stdinChunks is closed
+
+	stdinMu      sync.Mutex
+	stdinBuf     []byte
+	stdinPending bool // a read was asked for and has not been handed out yet
+)
+
+func readStdin() {
+	tty := term.IsTerminal(int(os.Stdin.Fd()))
+	buf := make([]byte, 4096)
+	for range stdinWant {
+		if !waitStdin() {
+			stdinChunks <- []byte{}
+			continue
+		}
+		n, err := os.Stdin.Read(buf)
+		if n > 0 {
+			stdinChunks <- append([]byte(nil), buf[:n]...)
+			continue
+		}
+		if err == io.EOF && tty {
+			// Ctrl-D: the terminal can still be read after it.
+			stdinChunks <- nil
+			continue
+		}
+		if err != nil {
+			stdinErr = err
+			close(stdinChunks)
+			return
+		}
+		stdinChunks <- []byte{}
+	}
+}
+
+// readByteContext returns the next byte of stdin.
+func readByteContext(ctx context.Context) (byte, error) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+
+	for len(stdinBuf) == 0 {
+		stdinOnce.Do(func() { go readStdin() })
+		if !stdinPending {
+			stdinWant <- struct{}{}
+			stdinPending = true
+		}
+		select {
+		case chunk, ok := <-stdinChunks:
+			if !ok {
+				return 0, stdinErr
+			}
+			stdinPending = false
+			if chunk == nil {
+				return 0, io.EOF
+			}
+			stdinBuf = chunk
+		case <-ctx.Done():
+			if interruptStdin() {
+				// Wait for the read to end rather than leave it in flight,
+				// keeping what it read if it got there first.
+				chunk, ok := <-stdinChunks
+				clearStdinInterrupt()
+				if ok {
+					stdinPending = false
+					stdinBuf = append(stdinBuf, chunk...)
+				}
+			}
+			return 0, ctx.Err()
+		}
+	}
+	c := stdinBuf[0]
+	stdinBuf = stdinBuf[1:]
+	return c, nil
+}
+
+// unreadByte puts c back in front of what is left to read.
+func unreadByte(c byte) {
+	stdinMu.Lock()
+	defer stdinMu.Unlock()
+	stdinBuf = append([]byte{c}, stdinBuf...)
+}
+
+// readLine reads one line from stdin without its line ending.
+func readLine() (string, error) {
+	return readLineContext(context.Background())
+}
+
+func readLineContext(ctx context.Context) (string, error) {
+	var line strings.Builder
+	for {
+		c, err := readByteContext(ctx)
+		if err != nil {
+			if line.Len() > 0 && ctx.Err() == nil {
+				return line.String(), nil // last line without a newline
+			}
+			return "", err
+		}
+		if c == '\n' {
+			return strings.TrimSuffix(line.String(), "\r"), nil
+		}
+		line.WriteByte(c)
+	}
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package onylogger
+
+// Reads of stdin cannot be interrupted here: a prompt that stops waiting
+// leaves its read in flight, and the next prompt gets what it reads.
+
+func waitStdin() bool { return true }
+
+func interruptStdin() bool { return false }
+
+func clearStdinInterrupt() {}
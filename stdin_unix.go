@@ -0,0 +1,74 @@
+//go:build unix
+
+package onylogger
+
+import (
+	"os"
+	"sync"
+
+	"golang.org/x/sys/unix"
+)
+
+// A read of stdin waits in poll, along with a pipe that interruptStdin
+// writes to, so that it can be given up without taking any input.
+var (
+	stdinPipeOnce sync.Once
+	stdinPipe     [2]int
+	stdinPipeErr  error
+)
+
+func openStdinPipe() error {
+	stdinPipeOnce.Do(func() {
+		stdinPipeErr = unix.Pipe(stdinPipe[:])
+		if stdinPipeErr == nil {
+			stdinPipeErr = unix.SetNonblock(stdinPipe[0], true)
+		}
+	})
+	return stdinPipeErr
+}
+
+// waitStdin blocks until stdin can be read, returning false if the read
+// was interrupted instead. When stdin cannot be polled it returns true
+// and the read blocks.
+func waitStdin() bool {
+	if openStdinPipe() != nil {
+		return true
+	}
+	fds := []unix.PollFd{
+		{Fd: int32(os.Stdin.Fd()), Events: unix.POLLIN},
+		{Fd: int32(stdinPipe[0]), Events: unix.POLLIN},
+	}
+	for {
+		_, err := unix.Poll(fds, -1)
+		if err == unix.EINTR {
+			continue
+		}
+		if err != nil || fds[0].Revents != 0 {
+			return true
+		}
+		if fds[1].Revents != 0 {
+			return false
+		}
+	}
+}
+
+// interruptStdin has the read waiting in waitStdin, or about to, give up.
+// It returns false if reads cannot be interrupted.
+func interruptStdin() bool {
+	if openStdinPipe() != nil {
+		return false
+	}
+	_, err := unix.Write(stdinPipe[1], []byte{0})
+	return err == nil
+}
+
+// clearStdinInterrupt takes back the interruption once the read is over,
+// whether it gave up or had already read.
+func clearStdinInterrupt() {
+	var buf [16]byte
+	for {
+		if n, _ := unix.Read(stdinPipe[0], buf[:]); n <= 0 {
+			return
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package onylogger
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"strings"
+	"sync"
+)
+
+// StripANSIWriter returns a writer that drops terminal escape sequences,
+// colors and hyperlinks alike, before writing to w, keeping the text they
+// wrap. A sequence split across writes is held back until it completes.
+//
+// File and remote sinks already write through one (the fluentd sink, whose
+// frames are binary, strips what it encodes instead), as does the console
+// when it is pointed at a regular file with SetOutput.
+func StripANSIWriter(w io.Writer) io.Writer {
+	return &stripANSIWriter{w: w}
+}
+
+type stripANSIWriter struct {
+	w io.Writer
+
+	mu      sync.Mutex
+	pending []byte // an incomplete escape sequence from the last write
+}
+
+func (s *stripANSIWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	b := p
+	if len(s.pending) > 0 {
+		b = append(s.pending, p...)
+		s.pending = nil
+	}
+	out, rest := stripANSI(b)
+	if len(rest) > 0 {
+		s.pending = append([]byte(nil), rest...)
+	}
+	if len(out) > 0 {
+		if _, err := s.w.Write(out); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// stripANSI returns b without its escape sequences, and the unterminated
+// sequence b ends with, if any.
+func stripANSI(b []byte) (out, rest []byte) {
+	if bytes.IndexByte(b, '\033') < 0 {
+		return b, nil
+	}
+	out = make([]byte, 0, len(b))
+	for len(b) > 0 {
+		i := bytes.IndexByte(b, '\033')
+		if i < 0 {
+			return append(out, b...), nil
+		}
+		out = append(out, b[:i]...)
+		b = b[i:]
+		n := escapeLen(string(b))
+		if n == len(b) && !escapeComplete(b) {
+			return out, b
+		}
+		b = b[n:]
+	}
+	return out, nil
+}
+
+// stripANSIString is stripANSI for a whole string, where an unterminated
+// sequence is dropped as well.
+func stripANSIString(s string) string {
+	if !strings.Contains(s, "\033") {
+		return s
+	}
+	out, _ := stripANSI([]byte(s))
+	return string(out)
+}
+
+// escapeComplete reports whether the escape sequence b is terminated, as
+// escapeLen counts an unterminated one as running to the end.
+func escapeComplete(b []byte) bool {
+	if len(b) < 2 {
+		return false
+	}
+	last := b[len(b)-1]
+	switch b[1] {
+	case '[':
+		return len(b) > 2 && last >= 0x40 && last <= 0x7e
+	case ']':
+		return last == '\a' || bytes.HasSuffix(b, []byte("\033\\"))
+	}
+	return true
+}
+
+// isRegularFile reports whether w is a plain file, as opposed to a terminal,
+// pipe or device that might render escape sequences.
+func isRegularFile(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := f.Stat()
+	return err == nil && info.Mode().IsRegular()
+}
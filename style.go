@@ -0,0 +1,88 @@
+package onylogger
+
+import (
+	"os"
+	"runtime"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Style selects how the level of an entry is shown.
+type Style int
+
+const (
+	// StyleEmoji prefixes entries with the level emoji, e.g. "[📜]".
+	StyleEmoji Style = iota
+	// StyleTags prefixes entries with a bracketed level tag, e.g. "[INFO]",
+	// for terminals and viewers that cannot render emoji.
+	StyleTags
+	// StyleTagsAndEmoji shows the level tag followed by the emoji.
+	StyleTagsAndEmoji
+)
+
+var levelTags = [...]string{
+	logrus.PanicLevel: "[PANIC]",
+	logrus.FatalLevel: "[FATAL]",
+	logrus.ErrorLevel: "[ERROR]",
+	logrus.WarnLevel:  "[WARN]",
+	logrus.InfoLevel:  "[INFO]",
+	logrus.DebugLevel: "[DEBUG]",
+	logrus.TraceLevel: "[TRACE]",
+}
+
+const inputTag = "[INPUT]"
+
+func levelTag(level logrus.Level) string {
+	if int(level) < len(levelTags) {
+		return levelTags[level]
+	}
+	return "[" + strings.ToUpper(level.String()) + "]"
+}
+
+func (s Style) tags() bool {
+	return s == StyleTags || s == StyleTagsAndEmoji
+}
+
+func (s Style) emoji() bool {
+	return s == StyleEmoji || s == StyleTagsAndEmoji
+}
+
+// WithStyle sets how levels are shown on the console. Without it the
+// console uses StyleEmoji, or StyleTags on terminals that are unlikely to
+// render emoji.
+func WithStyle(style Style) Option {
+	return func(o *options) {
+		o.style = style
+		o.styleSet = true
+	}
+}
+
+// canRenderEmoji guesses whether the terminal shows emoji rather
+// than mojibake: the locale must be UTF-8, and on Windows the classic
+// console host cannot, while Windows Terminal and VS Code can.
+func canRenderEmoji() bool {
+	if runtime.GOOS == "windows" {
+		return os.Getenv("WT_SESSION") != "" || os.Getenv("TERM_PROGRAM") == "vscode" ||
+			os.Getenv("ConEmuANSI") == "ON"
+	}
+	if os.Getenv("TERM") == "linux" {
+		return false // the kernel's virtual console
+	}
+	locale := os.Getenv("LC_ALL")
+	if locale == "" {
+		locale = os.Getenv("LC_CTYPE")
+	}
+	if locale == "" {
+		locale = os.Getenv("LANG")
+	}
+	locale = strings.ToLower(locale)
+	return strings.Contains(locale, "utf-8") || strings.Contains(locale, "utf8")
+}
+
+// FileStyle sets how levels are shown in a file sink.
+func FileStyle(style Style) FileOption {
+	return func(fc *fileConfig) {
+		fc.style = style
+	}
+}
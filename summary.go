@@ -0,0 +1,106 @@
+package onylogger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithSummaryOnClose logs the Summary report when the logger is closed.
+func WithSummaryOnClose() Option {
+	return func(o *options) {
+		o.summaryOnClose = true
+	}
+}
+
+// stats is a hook tallying what the logger has written, for Summary.
+type stats struct {
+	started time.Time
+	counts  [logrus.TraceLevel + 1]atomic.Uint64
+
+	mu       sync.Mutex
+	firstErr *recordedError
+	lastErr  *recordedError
+}
+
+type recordedError struct {
+	time    time.Time
+	message string
+}
+
+func newStats() *stats {
+	return &stats{started: time.Now()}
+}
+
+func (s *stats) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (s *stats) Fire(entry *logrus.Entry) error {
+	if demoted(entry, s.Levels()) {
+		return nil
+	}
+	if int(entry.Level) < len(s.counts) {
+		s.counts[entry.Level].Add(1)
+	}
+	if entry.Level > logrus.ErrorLevel {
+		return nil
+	}
+
+	rec := &recordedError{time: entry.Time, message: plainLinks(entry.Message)}
+	s.mu.Lock()
+	if s.firstErr == nil {
+		s.firstErr = rec
+	}
+	s.lastErr = rec
+	s.mu.Unlock()
+	return nil
+}
+
+// Summary logs a recap of the run so far: total runtime, the number of
+// entries per level, and the first and last error.
+func (l *OnyLogger) Summary() {
+	if !l.enabled(InfoLevel) {
+		return
+	}
+
+	s := l.stats
+	elapsed := time.Since(s.started).Round(time.Millisecond)
+	fields := logrus.Fields{
+		"emoji":   "[📊] ",
+		"runtime": elapsed.String(),
+	}
+
+	var counts []string
+	for _, level := range logrus.AllLevels {
+		n := s.counts[level].Load()
+		if n == 0 {
+			continue
+		}
+		counts = append(counts, fmt.Sprintf("%s=%d", level, n))
+		fields["count_"+level.String()] = n
+	}
+	if len(counts) == 0 {
+		counts = append(counts, "no entries")
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Summary: ran for %s", elapsed)
+	fmt.Fprintf(&report, "\n    %-12s %s", "entries", strings.Join(counts, " "))
+
+	s.mu.Lock()
+	first, last := s.firstErr, s.lastErr
+	s.mu.Unlock()
+	if first != nil {
+		fmt.Fprintf(&report, "\n    %-12s [%s] %s", "first error", first.time.Format(timestampLayout), first.message)
+		fmt.Fprintf(&report, "\n    %-12s [%s] %s", "last error", last.time.Format(timestampLayout), last.message)
+		fields["first_error"] = first.message
+		fields["last_error"] = last.message
+	}
+
+	l.emit(InfoLevel, fields, report.String())
+}
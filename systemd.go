@@ -0,0 +1,58 @@
+package onylogger
+
+import (
+	"bytes"
+	"strconv"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithSystemdPrefixes starts every console line with the "<N>" syslog
+// priority prefix of sd-daemon(3), so that journald keeps the severity of
+// entries read from a service's stdout or stderr. Colors are turned off,
+// since the journal shows them as escape codes.
+func WithSystemdPrefixes() Option {
+	return func(o *options) {
+		o.systemdPrefixes = true
+	}
+}
+
+// syslogPriorities is indexed by logrus.Level.
+var syslogPriorities = [...]int{
+	logrus.PanicLevel: 2, // crit
+	logrus.FatalLevel: 2,
+	logrus.ErrorLevel: 3, // err
+	logrus.WarnLevel:  4, // warning
+	logrus.InfoLevel:  6, // info
+	logrus.DebugLevel: 7, // debug
+	logrus.TraceLevel: 7,
+}
+
+// systemdFormatter prefixes every line of what next formats; journald reads
+// each line as a record of its own.
+type systemdFormatter struct {
+	next entryFormatter
+}
+
+func (f systemdFormatter) format(e *Entry, _ *bytes.Buffer) ([]byte, error) {
+	b, err := f.next.format(e, nil)
+	if err != nil {
+		return nil, err
+	}
+	priority := 6
+	if int(e.Level) < len(syslogPriorities) {
+		priority = syslogPriorities[e.Level]
+	}
+	prefix := []byte("<" + strconv.Itoa(priority) + ">")
+
+	out := make([]byte, 0, len(b)+len(prefix))
+	for len(b) > 0 {
+		n := bytes.IndexByte(b, '\n') + 1
+		if n == 0 {
+			n = len(b)
+		}
+		out = append(append(out, prefix...), b[:n]...)
+		b = b[n:]
+	}
+	return out, nil
+}
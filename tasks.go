@@ -0,0 +1,123 @@
+package onylogger
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// TaskSet tracks a known number of tasks, such as the jobs of a worker
+// pool, like a sync.WaitGroup that reports: while tasks are running, a
+// live "7/10 done, 1 failed" line is shown below the log, and once all of
+// them are done, a summary entry is logged, at Error level with every task
+// error if any failed.
+type TaskSet struct {
+	l       *OnyLogger
+	name    string
+	total   int
+	started time.Time
+
+	mu     sync.Mutex
+	done   int
+	errs   []error
+	finish chan struct{}
+}
+
+// Tasks starts tracking total tasks called name (see TaskSet).
+//
+//	ts := log.Tasks("workers", len(jobs))
+//	for _, job := range jobs {
+//		go func() { ts.Done(job.Run()) }()
+//	}
+//	ts.Wait()
+func (l *OnyLogger) Tasks(name string, total int) *TaskSet {
+	ts := &TaskSet{
+		l:       l,
+		name:    name,
+		total:   total,
+		started: time.Now(),
+		finish:  make(chan struct{}),
+	}
+	if total <= 0 {
+		ts.end()
+		return ts
+	}
+	l.render.add(ts)
+	return ts
+}
+
+func (ts *TaskSet) render() string {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	line := fmt.Sprintf("%s: %d/%d done", ts.name, ts.done, ts.total)
+	if len(ts.errs) > 0 {
+		line += fmt.Sprintf(", %d failed", len(ts.errs))
+	}
+	return colorMagenta + "[⏳]" + colorReset + " " + line
+}
+
+// Done marks a task as done, failed if err is not nil. Calls past the
+// total are ignored.
+func (ts *TaskSet) Done(err error) {
+	ts.mu.Lock()
+	if ts.done == ts.total {
+		ts.mu.Unlock()
+		return
+	}
+	ts.done++
+	if err != nil {
+		ts.errs = append(ts.errs, err)
+	}
+	last := ts.done == ts.total
+	ts.mu.Unlock()
+
+	if !last {
+		ts.l.render.refresh()
+		return
+	}
+	ts.l.render.remove(ts)
+	ts.end()
+}
+
+// Wait blocks until every task is done and the summary is logged.
+func (ts *TaskSet) Wait() {
+	<-ts.finish
+}
+
+// Errors returns the errors of the failed tasks so far, in the order they
+// were reported.
+func (ts *TaskSet) Errors() []error {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return append([]error(nil), ts.errs...)
+}
+
+// end logs the summary.
+func (ts *TaskSet) end() {
+	defer close(ts.finish)
+
+	elapsed := time.Since(ts.started).Round(time.Millisecond)
+	errs := ts.Errors()
+	fields := Fields{
+		"tasks":    ts.total,
+		"failed":   len(errs),
+		"duration": elapsed.String(),
+	}
+	if len(errs) == 0 {
+		fields["emoji"] = "[✅] "
+		ts.l.emit(InfoLevel, fields, fmt.Sprintf("%s: %d tasks done in %s", ts.name, ts.total, elapsed))
+		return
+	}
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "%s: %d tasks done in %s, %d failed:", ts.name, ts.total, elapsed, len(errs))
+	for _, err := range errs {
+		fmt.Fprintf(&report, "\n    %v", err)
+	}
+	fields[logrus.ErrorKey] = errors.Join(errs...)
+	ts.l.emit(ErrorLevel, fields, report.String())
+}
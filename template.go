@@ -0,0 +1,62 @@
+package onylogger
+
+import (
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// T logs a message template at Info level. Every {name} placeholder is
+// replaced by the value of that field in the message, and fields are also
+// attached to the entry, together with the template itself in a "template"
+// field, so JSON outputs can group entries by template:
+//
+//	log.T("Deploying {app} to {env}", onylogger.Fields{"app": "api", "env": "prod"})
+//
+// Placeholders without a field are left as they are; "{{" writes a literal "{".
+func (l *OnyLogger) T(template string, fields Fields) {
+	l.LogT(logrus.InfoLevel, template, fields)
+}
+
+// LogT is T at the given level.
+func (l *OnyLogger) LogT(level Level, template string, fields Fields) {
+	if !l.enabled(level) {
+		return
+	}
+	data := make(Fields, len(fields)+1)
+	for k, v := range fields {
+		data[k] = v
+	}
+	data["template"] = template
+	l.emit(level, data, expandTemplate(template, fields))
+}
+
+func expandTemplate(template string, fields logrus.Fields) string {
+	var b strings.Builder
+	for {
+		i := strings.IndexByte(template, '{')
+		if i < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		b.WriteString(template[:i])
+		template = template[i:]
+
+		if strings.HasPrefix(template, "{{") {
+			b.WriteByte('{')
+			template = template[2:]
+			continue
+		}
+		end := strings.IndexByte(template, '}')
+		if end < 0 {
+			b.WriteString(template)
+			return b.String()
+		}
+		if v, ok := fields[template[1:end]]; ok {
+			b.WriteString(fieldString(v))
+		} else {
+			b.WriteString(template[:end+1])
+		}
+		template = template[end+1:]
+	}
+}
@@ -0,0 +1,55 @@
+package onylogger
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// NewForTest returns a logger for code under test that writes its console
+// output through t.Log, so it is shown with the test that logged it and
+// only when that test fails or runs with -v. The console formatter runs as
+// usual, without colors. The logger is closed when the test finishes, and
+// a Fatal entry fails the test instead of exiting.
+func NewForTest(t testing.TB, opts ...Option) *OnyLogger {
+	l := New(opts...)
+	if f, ok := l.textConsole(); ok {
+		f.noColor, f.links = true, false
+	}
+
+	w := &testWriter{t: t}
+	l.SetOutput(w)
+	l.ExitFunc = func(code int) {
+		t.Fatalf("Fatal entry logged, exit code %d", code)
+	}
+	t.Cleanup(func() {
+		l.Close()
+		w.stop()
+	})
+	return l
+}
+
+// testWriter logs each write through t.Log until the test is over, after
+// which testing would panic.
+type testWriter struct {
+	t testing.TB
+
+	mu   sync.Mutex
+	done bool
+}
+
+func (w *testWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if !w.done {
+		w.t.Log(strings.TrimSuffix(string(p), "\n"))
+	}
+	return len(p), nil
+}
+
+func (w *testWriter) stop() {
+	w.mu.Lock()
+	w.done = true
+	w.mu.Unlock()
+}
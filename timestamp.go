@@ -0,0 +1,109 @@
+package onylogger
+
+import (
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+type timeMode int
+
+const (
+	timeAbsolute timeMode = iota
+	timeElapsed
+	timeNone
+)
+
+// Precision is the resolution timestamps are rendered with.
+type Precision int
+
+const (
+	PrecisionSeconds Precision = iota
+	PrecisionMillis
+	PrecisionMicros
+)
+
+// WithTimestampPrecision sets the timestamp resolution of every output, text
+// and JSON alike.
+func WithTimestampPrecision(precision Precision) Option {
+	return func(o *options) {
+		o.precision = precision
+	}
+}
+
+func (p Precision) tick() time.Duration {
+	switch p {
+	case PrecisionMillis:
+		return time.Millisecond
+	case PrecisionMicros:
+		return time.Microsecond
+	}
+	return time.Second
+}
+
+// fraction is the layout suffix for the sub-second part.
+func (p Precision) fraction() string {
+	switch p {
+	case PrecisionMillis:
+		return ".000"
+	case PrecisionMicros:
+		return ".000000"
+	}
+	return ""
+}
+
+// processStart is the reference point for elapsed timestamps.
+var processStart = time.Now()
+
+// WithElapsedTime shows the time since program start (e.g. "+0.513s") on the
+// console instead of the wall-clock timestamp. File sinks keep wall-clock time.
+func WithElapsedTime() Option {
+	return func(o *options) {
+		o.timeMode = timeElapsed
+	}
+}
+
+// WithNoTimestamp leaves the timestamp off console entries entirely.
+func WithNoTimestamp() Option {
+	return func(o *options) {
+		o.timeMode = timeNone
+	}
+}
+
+func appendElapsed(b []byte, t time.Time) []byte {
+	b = append(b, '+')
+	b = strconv.AppendFloat(b, t.Sub(processStart).Seconds(), 'f', 3, 64)
+	return append(b, 's')
+}
+
+// timestampCache renders entry timestamps once per tick of the configured
+// precision and reuses the bytes for every entry landing in the same tick.
+// It is safe for concurrent use, since sinks format from hook goroutines.
+type timestampCache struct {
+	layout string
+	tick   time.Duration
+
+	last atomic.Pointer[cachedTimestamp]
+}
+
+type cachedTimestamp struct {
+	tick int64
+	loc  *time.Location
+	text []byte
+}
+
+func newTimestampCache(layout string, tick time.Duration) *timestampCache {
+	return &timestampCache{layout: layout, tick: tick}
+}
+
+// appendTo appends the formatted timestamp for t to b.
+func (c *timestampCache) appendTo(b []byte, t time.Time) []byte {
+	tick := t.UnixNano() / int64(c.tick)
+	if ts := c.last.Load(); ts != nil && ts.tick == tick && ts.loc == t.Location() {
+		return append(b, ts.text...)
+	}
+
+	text := t.AppendFormat(nil, c.layout)
+	c.last.Store(&cachedTimestamp{tick: tick, loc: t.Location(), text: text})
+	return append(b, text...)
+}
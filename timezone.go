@@ -0,0 +1,57 @@
+package onylogger
+
+import (
+	"time"
+)
+
+// WithTimezone shows console timestamps in loc instead of the local zone.
+func WithTimezone(loc *time.Location) Option {
+	return func(o *options) {
+		o.location = loc
+	}
+}
+
+// WithDualTime shows console timestamps in UTC followed by the local clock
+// time, e.g. "2006-01-02 12:00:00Z / 14:00:00 CEST". The local zone is the
+// one set with WithTimezone, if any.
+func WithDualTime() Option {
+	return func(o *options) {
+		o.dualTime = true
+	}
+}
+
+// FileTimezone writes the timestamps of a file sink, text or JSON, in loc
+// instead of the local zone.
+func FileTimezone(loc *time.Location) FileOption {
+	return func(fc *fileConfig) {
+		fc.location = loc
+	}
+}
+
+// setTimezone makes f render timestamps in loc.
+func setTimezone(f entryFormatter, loc *time.Location) {
+	switch f := f.(type) {
+	case *emojiFormatter:
+		f.location = loc
+	case *jsonFormatter:
+		f.location = loc
+	case *logfmtFormatter:
+		f.location = loc
+	case *dockerFormatter:
+		f.text.location = loc
+	}
+}
+
+// appendTime appends the timestamp of an entry logged at t.
+func (f *emojiFormatter) appendTime(b []byte, t time.Time) []byte {
+	local := t
+	if f.location != nil {
+		local = t.In(f.location)
+	}
+	if f.localClock == nil {
+		return f.timestamps.appendTo(b, local)
+	}
+	b = f.timestamps.appendTo(b, t.UTC())
+	b = append(b, "Z / "...)
+	return f.localClock.appendTo(b, local)
+}
@@ -0,0 +1,68 @@
+package onylogger
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"os"
+	"time"
+)
+
+// NewTLSConfig builds the TLS configuration of a network sink from PEM
+// files, for collectors that require mutual TLS. caFile, when set, replaces
+// the system roots the server certificate is verified against; certFile
+// and keyFile, when set, are the client certificate presented to it.
+//
+//	cfg, err := onylogger.NewTLSConfig("ca.pem", "client.pem", "client-key.pem")
+//	...
+//	onylogger.WithSocket("tcp", "collector:6514", onylogger.SocketTLS(cfg))
+func NewTLSConfig(caFile, certFile, keyFile string) (*tls.Config, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	if caFile != "" {
+		pem, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("onylogger: no certificates found in %s", caFile)
+		}
+	}
+	if certFile != "" || keyFile != "" {
+		cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return cfg, nil
+}
+
+// dial connects to addr, over TLS when cfg is set.
+func dial(network, addr string, timeout time.Duration, cfg *tls.Config) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	if cfg == nil {
+		return dialer.Dial(network, addr)
+	}
+	return tls.DialWithDialer(dialer, network, addr, cfg)
+}
+
+// startTLS upgrades conn, dialed to addr, to TLS.
+func startTLS(conn net.Conn, addr string, timeout time.Duration, cfg *tls.Config) (net.Conn, error) {
+	if cfg.ServerName == "" {
+		host, _, err := net.SplitHostPort(addr)
+		if err != nil {
+			host = addr
+		}
+		cfg = cfg.Clone()
+		cfg.ServerName = host
+	}
+	tlsConn := tls.Client(conn, cfg)
+	tlsConn.SetDeadline(time.Now().Add(timeout))
+	if err := tlsConn.Handshake(); err != nil {
+		return nil, err
+	}
+	tlsConn.SetDeadline(time.Time{})
+	return tlsConn, nil
+}
@@ -0,0 +1,127 @@
+package onylogger
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Transaction is a logger whose entries are held until Commit, then
+// written one after the other, with no entry of another goroutine in
+// between, and with the same "tx" and "tx_id" fields, so that the story of
+// one operation reads in one piece even when many run at once. A Fatal or
+// Panic entry commits the transaction, since the process will not get to.
+type Transaction struct {
+	*OnyLogger
+	buf *entryBuffer
+}
+
+// Begin starts a transaction called name (see Transaction). Like the
+// logger Err returns, it writes through l and shares its outputs.
+//
+//	tx := log.Begin("checkout")
+//	tx.Infof("Reserving %d items", len(items))
+//	...
+//	tx.Commit()
+func (l *OnyLogger) Begin(name string) *Transaction {
+	root := l
+	if l.root != nil {
+		root = l.root
+	}
+	buf := &entryBuffer{to: root.Logger, holds: ErrorLevel, flushes: FatalLevel, gate: root.gate}
+	return &Transaction{
+		OnyLogger: l.derive(logrus.Fields{"tx": name, "tx_id": newTxID()}, buf),
+		buf:       buf,
+	}
+}
+
+// Commit writes the entries of the transaction. Entries logged after
+// Commit are written right away.
+func (tx *Transaction) Commit() {
+	tx.buf.end(true)
+}
+
+// Rollback drops the entries of the transaction. Entries logged after
+// Rollback are written right away.
+func (tx *Transaction) Rollback() {
+	tx.buf.end(false)
+}
+
+func newTxID() string {
+	var id [8]byte
+	rand.Read(id[:])
+	return hex.EncodeToString(id[:])
+}
+
+// gate lets a transaction be written in one piece: while it is, the
+// entries of other goroutines are set aside, by dropping them (see
+// dropLevel), and logged again once it is done. It is the first hook of a
+// logger. Fatal and Panic entries are never set aside, as the process may
+// end with them.
+type gate struct {
+	to      *logrus.Logger
+	running atomic.Bool
+	commit  sync.Mutex // held while a transaction is written
+
+	mu      sync.Mutex
+	pending []heldEntry
+}
+
+// gateKey marks the context of the entries of the transaction being
+// written.
+type gateKey struct{}
+
+func (g *gate) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (g *gate) Fire(entry *logrus.Entry) error {
+	if !g.running.Load() || entry.Level <= logrus.FatalLevel {
+		return nil
+	}
+	if entry.Context != nil && entry.Context.Value(gateKey{}) == g {
+		return nil
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.running.Load() {
+		return nil
+	}
+	data := make(Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	g.pending = append(g.pending, heldEntry{level: entry.Level, time: entry.Time, ctx: entry.Context, fields: data, message: entry.Message})
+	entry.Level = dropLevel
+	return nil
+}
+
+// run writes held, then the entries set aside meanwhile.
+func (g *gate) run(held []heldEntry) {
+	g.commit.Lock()
+	defer g.commit.Unlock()
+	g.running.Store(true)
+	defer func() {
+		g.mu.Lock()
+		g.running.Store(false)
+		pending := g.pending
+		g.pending = nil
+		g.mu.Unlock()
+		for _, e := range pending {
+			e.log(g.to)
+		}
+	}()
+
+	for _, e := range held {
+		ctx := e.ctx
+		if ctx == nil {
+			ctx = context.Background()
+		}
+		e.ctx = context.WithValue(ctx, gateKey{}, g)
+		e.log(g.to)
+	}
+}
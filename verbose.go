@@ -0,0 +1,86 @@
+package onylogger
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sirupsen/logrus"
+)
+
+// WithVerbosity sets the threshold for V: V(n) logs when n is at most
+// verbosity. It also lowers the level far enough for those entries to show,
+// Debug for 1 and Trace from 2 on. Without it the threshold follows the
+// level: 1 at Debug, 2 at Trace and 0 otherwise.
+func WithVerbosity(verbosity int) Option {
+	return func(o *options) {
+		o.verbosity = &verbosity
+		level := verbosityLevel(verbosity)
+		if o.level == nil || *o.level < level {
+			o.level = &level
+		}
+	}
+}
+
+// verbosityLevel is the level V(n) entries are logged at.
+func verbosityLevel(n int) logrus.Level {
+	switch {
+	case n <= 0:
+		return logrus.InfoLevel
+	case n == 1:
+		return logrus.DebugLevel
+	}
+	return logrus.TraceLevel
+}
+
+// Verbose logs glog-style verbosity entries; see V.
+type Verbose struct {
+	l       *OnyLogger
+	level   logrus.Level
+	enabled bool
+}
+
+// V returns a logger for entries of verbosity n, for code ported from glog
+// or klog:
+//
+//	log.V(2).Infof("Cache miss for %s", key)
+//
+// V(0) logs at Info, V(1) at Debug and higher numbers at Trace. Entries are
+// dropped unless n is within the threshold set by WithVerbosity.
+func (l *OnyLogger) V(n int) Verbose {
+	threshold := 0
+	if l.opts.verbosity != nil {
+		threshold = *l.opts.verbosity
+	} else {
+		switch {
+		case l.enabled(TraceLevel):
+			threshold = 2
+		case l.enabled(DebugLevel):
+			threshold = 1
+		}
+	}
+	return Verbose{l: l, level: verbosityLevel(n), enabled: n <= threshold}
+}
+
+// Enabled reports whether entries at this verbosity are logged, to skip
+// work that only feeds them.
+func (v Verbose) Enabled() bool {
+	return v.enabled && v.l.enabled(v.level)
+}
+
+func (v Verbose) Info(args ...interface{}) {
+	if v.Enabled() {
+		v.l.emit(v.level, nil, fmt.Sprint(args...))
+	}
+}
+
+func (v Verbose) Infof(format string, args ...interface{}) {
+	if v.enabled {
+		v.l.emitf(v.level, nil, format, args...)
+	}
+}
+
+func (v Verbose) Infoln(args ...interface{}) {
+	if v.Enabled() {
+		v.l.emit(v.level, nil, strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+	}
+}
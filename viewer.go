@@ -0,0 +1,269 @@
+package onylogger
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/sirupsen/logrus"
+	"golang.org/x/term"
+)
+
+const defaultViewerEntries = 1000
+
+// WithViewer keeps the last entries in memory (1000 if entries is 0) so
+// that Viewer can browse them.
+func WithViewer(entries int) Option {
+	return func(o *options) {
+		if entries <= 0 {
+			entries = defaultViewerEntries
+		}
+		o.recentEntries = max(o.recentEntries, entries)
+	}
+}
+
+// Viewer is a full-screen terminal browser over the entries kept in memory,
+// with scrollback, level filtering and search. Entries logged to the console
+// while it is open are printed once it closes.
+//
+//	if key == 'L' {
+//		log.Viewer().Run(ctx)
+//	}
+type Viewer struct {
+	l *OnyLogger
+
+	level     logrus.Level // least severe level shown
+	search    string
+	searching bool   // typing a search
+	typed     []rune // the search being typed
+	offset    int    // matching entries hidden below the screen; 0 follows new ones
+
+	entries []Entry // matching entries, oldest first
+	seen    uint64
+	cols    int
+	rows    int
+}
+
+// Viewer returns a viewer over the entries kept by WithViewer or
+// WithCrashReport.
+func (l *OnyLogger) Viewer() *Viewer {
+	return &Viewer{l: l, level: logrus.TraceLevel}
+}
+
+// Run takes over the terminal until q is pressed or ctx is done.
+func (v *Viewer) Run(ctx context.Context) error {
+	if v.l.recent == nil {
+		return errors.New("onylogger: no entries are kept in memory, see WithViewer")
+	}
+	out, ok := v.l.render.out.(*os.File)
+	if !ok || !v.l.render.tty || !stdinIsTerminal() {
+		return ErrNonInteractive
+	}
+
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer term.Restore(fd, state)
+
+	v.l.render.hold()
+	defer v.l.render.release()
+	v.l.render.paint("\033[?1049h\033[?25l") // alternate screen, hidden cursor
+	defer v.l.render.paint("\033[?25h\033[?1049l")
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	keys := make(chan rune)
+	errs := make(chan error, 1)
+	go func() {
+		for {
+			r, err := readKey(ctx)
+			if err != nil {
+				errs <- err
+				return
+			}
+			select {
+			case keys <- r:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	tick := time.NewTicker(250 * time.Millisecond)
+	defer tick.Stop()
+	v.refresh(out, true)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case err := <-errs:
+			return err
+		case r := <-keys:
+			if !v.key(r) {
+				return nil
+			}
+			v.refresh(out, true)
+		case <-tick.C:
+			v.refresh(out, false)
+		}
+	}
+}
+
+// key handles one key press and reports whether the viewer stays open.
+func (v *Viewer) key(r rune) bool {
+	if v.searching {
+		switch r {
+		case '\r', '\n':
+			v.search, v.searching, v.offset = string(v.typed), false, 0
+		case keyCtrlC:
+			v.searching = false
+		case keyBackspace, keyDelete:
+			if len(v.typed) > 0 {
+				v.typed = v.typed[:len(v.typed)-1]
+			}
+		case keyCtrlU:
+			v.typed = nil
+		default:
+			if r >= ' ' && r < keyUnknown {
+				v.typed = append(v.typed, r)
+			}
+		}
+		return true
+	}
+
+	page := max(v.rows-2, 1)
+	switch r {
+	case 'q', keyCtrlC, keyCtrlD:
+		return false
+	case keyUp, 'k':
+		v.offset++
+	case keyDown, 'j':
+		v.offset--
+	case keyPageUp, 'b':
+		v.offset += page
+	case keyPageDown, ' ':
+		v.offset -= page
+	case keyHome, 'g':
+		v.offset = len(v.entries)
+	case keyEnd, 'G':
+		v.offset = 0
+	case '/':
+		v.searching, v.typed = true, []rune(v.search)
+	case 'e':
+		v.level, v.offset = logrus.ErrorLevel, 0
+	case 'w':
+		v.level, v.offset = logrus.WarnLevel, 0
+	case 'i':
+		v.level, v.offset = logrus.InfoLevel, 0
+	case 'd':
+		v.level, v.offset = logrus.DebugLevel, 0
+	case 't', 'a':
+		v.level, v.offset = logrus.TraceLevel, 0
+	}
+	return true
+}
+
+// refresh redraws the screen if forced, or if entries came in or the
+// terminal was resized since the last draw.
+func (v *Viewer) refresh(out *os.File, force bool) {
+	cols, rows, err := term.GetSize(int(out.Fd()))
+	if err != nil {
+		cols, rows = 80, 24
+	}
+	seen := v.l.recent.seen()
+	if !force && seen == v.seen && cols == v.cols && rows == v.rows {
+		return
+	}
+	v.seen, v.cols, v.rows = seen, cols, rows
+
+	v.entries = v.entries[:0]
+	for _, e := range v.l.recent.snapshot() {
+		if e.Level <= v.level && v.matches(e) {
+			v.entries = append(v.entries, e)
+		}
+	}
+	v.draw()
+}
+
+func (v *Viewer) matches(e Entry) bool {
+	return v.search == "" || strings.Contains(strings.ToLower(viewerLine(e)), strings.ToLower(v.search))
+}
+
+func (v *Viewer) draw() {
+	height := max(v.rows-2, 1)
+	v.offset = min(max(v.offset, 0), max(len(v.entries)-height, 0))
+	end := len(v.entries) - v.offset
+	start := max(end-height, 0)
+
+	var b strings.Builder
+	b.WriteString("\033[H")
+	v.writeStatus(&b)
+	for i := 0; i < height; i++ {
+		fmt.Fprintf(&b, "\033[%dH\033[K", i+2)
+		if start+i < end {
+			b.WriteString(v.entryLine(v.entries[start+i]))
+		}
+	}
+	fmt.Fprintf(&b, "\033[%dH\033[K", v.rows)
+	if v.searching {
+		b.WriteString("/" + string(v.typed))
+	} else {
+		b.WriteString(colorDim + fit("↑↓ PgUp PgDn scroll  g G top/bottom  e w i d t level  / search  q quit", v.cols) + colorReset)
+	}
+	v.l.render.paint(b.String())
+}
+
+func (v *Viewer) writeStatus(b *strings.Builder) {
+	status := fmt.Sprintf(" %d entries  level: %s", len(v.entries), v.level)
+	if v.search != "" {
+		status += fmt.Sprintf("  search: %q", v.search)
+	}
+	if v.offset > 0 {
+		status += fmt.Sprintf("  %d below", v.offset)
+	} else {
+		status += "  following"
+	}
+	status = fit(status, v.cols)
+	b.WriteString("\033[K\033[7m" + status + strings.Repeat(" ", max(v.cols-visibleWidth([]byte(status)), 0)) + colorReset)
+}
+
+// entryLine renders e on one line of the screen, highlighting the search.
+func (v *Viewer) entryLine(e Entry) string {
+	line := fit(viewerLine(e), v.cols)
+	if v.search != "" {
+		lower := strings.ToLower(line)
+		if i := strings.Index(lower, strings.ToLower(v.search)); i >= 0 && len(lower) == len(line) {
+			n := len(v.search)
+			line = line[:i] + "\033[7m" + line[i:i+n] + "\033[27m" + line[i+n:]
+		}
+	}
+	return levelColor(e.Level) + line + colorReset
+}
+
+// viewerLine is the plain text of e as the viewer shows it.
+func viewerLine(e Entry) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s %-7s %s", e.Time.Format("15:04:05.000"), levelTag(e.Level), e.Message)
+
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%s", k, formatFieldValue(e.Fields[k]))
+	}
+	return strings.NewReplacer("\r", "", "\n", " ", "\t", " ", "\033", "").Replace(b.String())
+}
+
+// fit cuts s to the given number of columns.
+func fit(s string, cols int) string {
+	head, _ := splitAtWidth(s, cols)
+	return head
+}
@@ -0,0 +1,103 @@
+package onylogger
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Watchdog reports a component that stopped making progress. See
+// OnyLogger.Watchdog.
+type Watchdog struct {
+	l      *OnyLogger
+	name   string
+	window time.Duration
+
+	mu       sync.Mutex
+	timer    *time.Timer
+	lastKick time.Time
+	missed   int // windows missed so far: 1 warned, 2 errored
+	stopped  bool
+}
+
+// Watchdog starts watching the component called name. Unless Kick is called
+// at least once per window, it logs a Warn, and an Error once the component
+// has been silent for two windows. A Kick after that logs that the
+// component is back.
+//
+// A window that is not positive is reported (see SetErrorHandler) and the
+// watchdog does nothing.
+//
+//	w := log.Watchdog("consumer", 2*time.Minute)
+//	defer w.Stop()
+//	for msg := range msgs {
+//		w.Kick()
+//		...
+//	}
+func (l *OnyLogger) Watchdog(name string, window time.Duration) *Watchdog {
+	w := &Watchdog{l: l, name: name, window: window, lastKick: time.Now()}
+	if window <= 0 {
+		l.reportError(fmt.Errorf("invalid watchdog window %s for %s", window, name))
+		w.stopped = true
+		return w
+	}
+	w.timer = time.AfterFunc(window, w.expire)
+	return w
+}
+
+// Kick tells the watchdog the component is alive.
+func (w *Watchdog) Kick() {
+	w.mu.Lock()
+	if w.stopped {
+		w.mu.Unlock()
+		return
+	}
+	silent, missed := time.Since(w.lastKick), w.missed
+	w.lastKick, w.missed = time.Now(), 0
+	w.timer.Reset(w.window)
+	w.mu.Unlock()
+
+	if missed > 0 {
+		silent = silent.Round(time.Millisecond)
+		w.l.emitf(InfoLevel, Fields{
+			"watchdog": w.name,
+			"silent":   silent.String(),
+		}, "%s is active again after %s", w.name, silent)
+	}
+}
+
+// Stop stops watching. No further entries are logged.
+func (w *Watchdog) Stop() {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.stopped = true
+	if w.timer != nil {
+		w.timer.Stop()
+	}
+}
+
+func (w *Watchdog) expire() {
+	w.mu.Lock()
+	if w.stopped || w.missed >= 2 || time.Since(w.lastKick) < w.window {
+		// Stopped, already reported, or kicked while this timer fired.
+		w.mu.Unlock()
+		return
+	}
+	w.missed++
+	missed, silent := w.missed, time.Since(w.lastKick).Round(time.Millisecond)
+	if missed < 2 {
+		w.timer.Reset(w.window)
+	}
+	w.mu.Unlock()
+
+	level := logrus.WarnLevel
+	if missed >= 2 {
+		level = logrus.ErrorLevel
+	}
+	w.l.emitf(level, Fields{
+		"watchdog": w.name,
+		"silent":   silent.String(),
+	}, "%s has been silent for %s", w.name, silent)
+}
@@ -0,0 +1,222 @@
+package onylogger
+
+import (
+	"bytes"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+// WithSoftWrap wraps long console lines at word boundaries to the width of
+// the terminal, indenting continuation lines to where the message starts.
+// The width follows terminal resizes. Output that is not a terminal is
+// never wrapped.
+func WithSoftWrap() Option {
+	return func(o *options) {
+		o.softWrap = true
+	}
+}
+
+// columns returns the width of the console terminal, or 0 if the console is
+// not a terminal.
+func (r *renderer) columns() int {
+	if !haveResizeSignal && time.Since(time.Unix(0, r.measured.Load())) > time.Second {
+		r.mu.Lock()
+		r.measure()
+		r.mu.Unlock()
+	}
+	return int(r.cols.Load())
+}
+
+// measure must be called with mu held.
+func (r *renderer) measure() {
+	var cols int
+	if f, ok := r.out.(*os.File); ok && r.tty {
+		cols, _, _ = term.GetSize(int(f.Fd()))
+	}
+	r.cols.Store(int64(cols))
+	r.measured.Store(time.Now().UnixNano())
+}
+
+// resizeWatcher measures the terminal again whenever it is resized.
+type resizeWatcher struct {
+	signals chan os.Signal
+	done    chan struct{}
+}
+
+func (l *OnyLogger) watchResize() {
+	w := &resizeWatcher{signals: make(chan os.Signal, 1), done: make(chan struct{})}
+	notifyResize(w.signals)
+
+	go func() {
+		defer close(w.done)
+		for range w.signals {
+			l.render.mu.Lock()
+			l.render.measure()
+			l.render.mu.Unlock()
+		}
+	}()
+	l.closers = append(l.closers, w)
+}
+
+func (w *resizeWatcher) Close() error {
+	signal.Stop(w.signals)
+	close(w.signals)
+	<-w.done
+	return nil
+}
+
+// softWrap wraps the formatted line b at cols, where the first indent
+// columns are taken by the entry's prefix and b[start:] is the message.
+// Lines after a newline in the message keep their own indentation.
+func softWrap(b []byte, start, cols int) []byte {
+	indent := visibleWidth(b[:start])
+	if indent > cols/2 {
+		indent = 0 // too narrow to indent usefully
+	}
+
+	var out bytes.Buffer
+	out.Write(b[:start])
+	for i, line := range strings.Split(string(b[start:]), "\n") {
+		if i == 0 {
+			wrapLine(&out, line, visibleWidth(b[:start]), indent, cols)
+			continue
+		}
+		out.WriteByte('\n')
+		lead := len(line) - len(strings.TrimLeft(line, " "))
+		if lead > cols/2 {
+			lead = 0
+		}
+		wrapLine(&out, line, 0, lead, cols)
+	}
+	return out.Bytes()
+}
+
+// wrapLine writes line starting at column col, breaking it between words
+// and continuing on new lines indented by indent columns. Words longer than
+// a line are broken, but never inside an escape sequence.
+func wrapLine(out *bytes.Buffer, line string, col, indent, cols int) {
+	lineStart := true
+	for _, word := range strings.Split(line, " ") {
+		width := visibleWidth([]byte(word))
+		switch {
+		case lineStart:
+		case col+1+width > cols && col > indent:
+			out.WriteByte('\n')
+			out.WriteString(strings.Repeat(" ", indent))
+			col = indent
+		default:
+			out.WriteByte(' ')
+			col++
+		}
+		lineStart = false
+
+		for col+width > cols && width > 0 && cols > indent {
+			head, rest := splitAtWidth(word, cols-col)
+			if head == "" {
+				break
+			}
+			out.WriteString(head)
+			out.WriteByte('\n')
+			out.WriteString(strings.Repeat(" ", indent))
+			col = indent
+			word, width = rest, visibleWidth([]byte(rest))
+		}
+		out.WriteString(word)
+		col += width
+	}
+}
+
+// splitAtWidth splits s after at most width visible columns.
+func splitAtWidth(s string, width int) (string, string) {
+	col := 0
+	for i := 0; i < len(s); {
+		if n := escapeLen(s[i:]); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		w := runeWidth(r)
+		if col+w > width {
+			return s[:i], s[i:]
+		}
+		col += w
+		i += size
+	}
+	return s, ""
+}
+
+// visibleWidth is the number of terminal columns b takes up.
+func visibleWidth(b []byte) int {
+	width := 0
+	for i := 0; i < len(b); {
+		if n := escapeLen(string(b[i:min(len(b), i+256)])); n > 0 {
+			i += n
+			continue
+		}
+		r, size := utf8.DecodeRune(b[i:])
+		width += runeWidth(r)
+		i += size
+	}
+	return width
+}
+
+// escapeLen returns the length of the CSI or OSC escape sequence s starts
+// with, or 0.
+func escapeLen(s string) int {
+	if len(s) < 2 || s[0] != '\033' {
+		return 0
+	}
+	switch s[1] {
+	case '[':
+		for i := 2; i < len(s); i++ {
+			if s[i] >= 0x40 && s[i] <= 0x7e {
+				return i + 1
+			}
+		}
+	case ']':
+		for i := 2; i < len(s); i++ {
+			if s[i] == '\a' {
+				return i + 1
+			}
+			if s[i] == '\033' && i+1 < len(s) && s[i+1] == '\\' {
+				return i + 2
+			}
+		}
+	default:
+		return 2
+	}
+	return len(s)
+}
+
+// runeWidth approximates how many columns a terminal gives r.
+func runeWidth(r rune) int {
+	switch {
+	case r == 0x200d, r >= 0xfe00 && r <= 0xfe0f, unicode.Is(unicode.Mn, r), unicode.Is(unicode.Me, r):
+		return 0 // joiners, variation selectors and combining marks
+	case r >= 0x1100 && r <= 0x115f, r >= 0x2e80 && r <= 0xa4cf, r >= 0xac00 && r <= 0xd7a3,
+		r >= 0xf900 && r <= 0xfaff, r >= 0xfe30 && r <= 0xfe4f, r >= 0xff00 && r <= 0xff60,
+		r >= 0xffe0 && r <= 0xffe6, r >= 0x1f300 && r <= 0x1f64f, r >= 0x1f680 && r <= 0x1f6ff,
+		r >= 0x1f900 && r <= 0x1f9ff, r >= 0x20000 && r <= 0x3fffd,
+		unicode.Is(wideSymbols, r):
+		return 2
+	}
+	return 1
+}
+
+// wideSymbols are the symbols below U+1F000 that terminals draw as emoji,
+// two columns wide.
+var wideSymbols = &unicode.RangeTable{R16: []unicode.Range16{
+	{0x231a, 0x231b, 1}, {0x23e9, 0x23ec, 1}, {0x23f0, 0x23f3, 3}, {0x25fd, 0x25fe, 1},
+	{0x2614, 0x2615, 1}, {0x2648, 0x2653, 1}, {0x267f, 0x2693, 20}, {0x26a1, 0x26a1, 1},
+	{0x26aa, 0x26ab, 1}, {0x26bd, 0x26be, 1}, {0x26c4, 0x26c5, 1}, {0x26ce, 0x26d4, 6},
+	{0x26ea, 0x26ea, 1}, {0x26f2, 0x26f3, 1}, {0x26f5, 0x26fa, 5}, {0x26fd, 0x2705, 8},
+	{0x270a, 0x270b, 1}, {0x2728, 0x2728, 1}, {0x274c, 0x274e, 2}, {0x2753, 0x2755, 1},
+	{0x2757, 0x2757, 1}, {0x2795, 0x2797, 1}, {0x27b0, 0x27bf, 15}, {0x2b1b, 0x2b1c, 1},
+	{0x2b50, 0x2b55, 5},
+}}
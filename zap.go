@@ -0,0 +1,89 @@
+package onylogger
+
+import (
+	"github.com/sirupsen/logrus"
+	"go.uber.org/zap/zapcore"
+)
+
+// NewZapCore returns a zapcore.Core that writes through l, so libraries
+// that take a *zap.Logger share l's formatters and sinks:
+//
+//	lib.New(zap.New(onylogger.NewZapCore(log)))
+//
+// Zap fields become fields, the logger name goes in "logger" and, when zap
+// records them, the caller in "caller" and the stack in "stack". Zap still
+// decides what happens after a Panic or Fatal entry; l records both as
+// Fatal without exiting, and DPanic as Error.
+func NewZapCore(l *OnyLogger) zapcore.Core {
+	return &zapCore{l: l}
+}
+
+type zapCore struct {
+	l      *OnyLogger
+	fields logrus.Fields
+}
+
+func zapLevel(level zapcore.Level) logrus.Level {
+	switch {
+	case level < zapcore.InfoLevel:
+		return logrus.DebugLevel
+	case level == zapcore.InfoLevel:
+		return logrus.InfoLevel
+	case level == zapcore.WarnLevel:
+		return logrus.WarnLevel
+	case level <= zapcore.DPanicLevel:
+		return logrus.ErrorLevel
+	}
+	// logrus panics on its Panic level; zap panics by itself after writing.
+	return logrus.FatalLevel
+}
+
+func (c *zapCore) Enabled(level zapcore.Level) bool {
+	return c.l.enabled(zapLevel(level))
+}
+
+func (c *zapCore) With(fields []zapcore.Field) zapcore.Core {
+	return &zapCore{l: c.l, fields: c.encode(fields)}
+}
+
+func (c *zapCore) Check(e zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(e.Level) {
+		return ce.AddCore(e, c)
+	}
+	return ce
+}
+
+func (c *zapCore) Write(e zapcore.Entry, fields []zapcore.Field) error {
+	data := c.encode(fields)
+	if e.LoggerName != "" {
+		data["logger"] = e.LoggerName
+	}
+	if e.Caller.Defined {
+		data["caller"] = e.Caller.TrimmedPath()
+	}
+	if e.Stack != "" {
+		data["stack"] = e.Stack
+	}
+	c.l.backend.log(zapLevel(e.Level), e.Time, data, e.Message)
+	return nil
+}
+
+func (c *zapCore) Sync() error {
+	return c.l.Flush()
+}
+
+// encode returns the core's fields with fields added.
+func (c *zapCore) encode(fields []zapcore.Field) logrus.Fields {
+	enc := zapcore.NewMapObjectEncoder()
+	for _, f := range fields {
+		f.AddTo(enc)
+	}
+	data := make(logrus.Fields, len(c.fields)+len(enc.Fields))
+	for k, v := range c.fields {
+		data[k] = v
+	}
+	for k, v := range enc.Fields {
+		data[k] = v
+	}
+	return data
+}